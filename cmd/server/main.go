@@ -5,7 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
 	"runtime"
+	"syscall"
 	"time"
 
 	"github.com/fadilmartias/cv-analyzer/internal/config"
@@ -14,7 +17,10 @@ import (
 	"github.com/fadilmartias/cv-analyzer/internal/model"
 	"github.com/fadilmartias/cv-analyzer/internal/repository"
 	"github.com/fadilmartias/cv-analyzer/internal/service"
+	"github.com/fadilmartias/cv-analyzer/internal/skills"
+	"github.com/fadilmartias/cv-analyzer/internal/storage"
 	"github.com/fadilmartias/cv-analyzer/internal/usecase"
+	"github.com/fadilmartias/cv-analyzer/internal/worker"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/compress"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -40,6 +46,8 @@ func main() {
 
 	app := fiber.New(fiber.Config{
 		AppName: appConfig.Name,
+		// Cukup untuk CV & project report ukuran maksimal plus field form lain
+		BodyLimit: int(2*config.LoadUploadConfig().MaxFileSize) + 1024*1024,
 		ErrorHandler: func(ctx *fiber.Ctx, err error) error {
 			// Status code defaults to 500
 			code := fiber.StatusInternalServerError
@@ -87,19 +95,74 @@ func main() {
 
 	jobRepo := repository.NewJobRepository(db)
 	evaluationRepo := repository.NewEvaluationRepository(db)
-	openRouter := service.NewOpenRouterService()
-	gemini, err := service.NewGeminiService(ctx)
+	queueRepo := repository.NewQueueRepository(db)
+	rubricRepo := repository.NewRubricRepository(db)
+	stageRepo := repository.NewEvaluationStageRepository(db)
+	documentRepo := repository.NewDocumentRepository(db)
+	candidateRepo := repository.NewCandidateRepository(db)
+	fileStorage, err := storage.New(config.LoadStorageConfig())
 	if err != nil {
 		log.Fatal(err)
 	}
-	uc := usecase.NewEvaluationUsecase(evaluationRepo, jobRepo, openRouter, gemini)
-	handler := handler.NewEvaluateHandler(uc)
+	log.Printf("Storage driver: %s", fileStorage.Name())
+	llm, embedder, err := service.NewLLMProviders(ctx, config.LoadLLMConfig())
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("LLM provider: %s, embedding provider: %s", llm.Name(), embedder.Name())
+	taxonomy, err := skills.Load(config.LoadSkillsConfig().TaxonomyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Skills taxonomy: %d skills", taxonomy.Len())
+	documentUc := usecase.NewDocumentUsecase(documentRepo, fileStorage, embedder)
+	candidateUc := usecase.NewCandidateUsecase(candidateRepo, evaluationRepo)
+	if err := candidateUc.Backfill(); err != nil {
+		log.Printf("Linking evaluations to candidates failed: %v", err)
+	}
+	uc := usecase.NewEvaluationUsecase(evaluationRepo, jobRepo, queueRepo, rubricRepo, stageRepo, documentUc, candidateUc, taxonomy, llm, embedder)
+	jobUc := usecase.NewJobUsecase(jobRepo, evaluationRepo, embedder, taxonomy)
+	if err := jobUc.RefreshSkills(); err != nil {
+		log.Printf("Extracting job skills failed: %v", err)
+	}
+	rubricUc := usecase.NewRubricUsecase(rubricRepo, jobRepo)
+	if err := rubricUc.EnsureDefault(); err != nil {
+		log.Printf("Seeding default rubric failed: %v", err)
+	}
 
-	handler.RegisterRoutes(app)
+	evaluateHandler := handler.NewEvaluateHandler(uc, documentUc)
+	jobHandler := handler.NewJobHandler(jobUc)
+	rubricHandler := handler.NewRubricHandler(rubricUc)
+	candidateHandler := handler.NewCandidateHandler(candidateUc)
+	healthHandler := handler.NewHealthHandler()
+
+	evaluateHandler.RegisterRoutes(app)
+	jobHandler.RegisterRoutes(app)
+	rubricHandler.RegisterRoutes(app)
+	candidateHandler.RegisterRoutes(app)
+	healthHandler.RegisterRoutes(app)
+
+	// Worker antrian evaluasi, berhenti saat SIGINT/SIGTERM
+	workerCtx, stopWorker := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopWorker()
+
+	evaluationWorker := worker.NewEvaluationWorker(queueRepo, uc, config.LoadQueueConfig(), config.LoadWorkerConfig())
+	if err := evaluationWorker.Recover(); err != nil {
+		log.Printf("Queue recovery failed: %v", err)
+	}
+	workerDone := make(chan struct{})
+	go func() {
+		evaluationWorker.Start(workerCtx)
+		close(workerDone)
+	}()
 
-	// Use context for cancellation
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+	go func() {
+		<-workerCtx.Done()
+		<-workerDone
+		if err := app.Shutdown(); err != nil {
+			log.Printf("Server shutdown failed: %v", err)
+		}
+	}()
 
 	// Monitor goroutine count
 	go func() {
@@ -151,8 +214,14 @@ func ConnectDB() *gorm.DB {
 
 	}
 
-	// migrasi tabel
-	err = db.AutoMigrate(&model.EvaluationTask{}, &model.Job{})
+	// migrasi tabel. Cache embedding sekarang unik per hash, model & key ekstraksi,
+	// index lama (hash, model) dibuang supaya tidak bentrok.
+	if db.Migrator().HasIndex(&model.DocumentEmbedding{}, "idx_document_embeddings_hash_model") {
+		if err := db.Migrator().DropIndex(&model.DocumentEmbedding{}, "idx_document_embeddings_hash_model"); err != nil {
+			log.Fatal("migration failed: ", err)
+		}
+	}
+	err = db.AutoMigrate(&model.EvaluationTask{}, &model.Job{}, &model.QueueJob{}, &model.Rubric{}, &model.EvaluationStage{}, &model.Document{}, &model.DocumentExtraction{}, &model.DocumentEmbedding{}, &model.Candidate{})
 	if err != nil {
 		log.Fatal("migration failed: ", err)
 	}
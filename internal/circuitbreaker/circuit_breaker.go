@@ -0,0 +1,265 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrOpen dikembalikan Allow saat breaker open atau kuota probe half-open habis
+var ErrOpen = errors.New("circuit breaker open")
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type Config struct {
+	// Window adalah panjang sliding window untuk menghitung failure rate
+	Window time.Duration
+	// MinRequests adalah jumlah request minimal di window sebelum breaker boleh open
+	MinRequests int
+	// FailureRate (0-1) di window yang membuat breaker open
+	FailureRate float64
+	// Cooldown adalah lama breaker open sebelum pindah ke half-open
+	Cooldown time.Duration
+	// MaxProbes adalah jumlah probe di half-open; semua harus sukses untuk close
+	MaxProbes int
+}
+
+type outcome struct {
+	at      time.Time
+	success bool
+}
+
+// Breaker adalah circuit breaker tiga state (closed → open → half-open → closed)
+// yang aman dipakai dari banyak goroutine
+type Breaker struct {
+	name string
+	cfg  Config
+
+	mu             sync.Mutex
+	state          State
+	outcomes       []outcome
+	openedAt       time.Time
+	probesInFlight int
+	probeSuccesses int
+}
+
+type Snapshot struct {
+	Name        string     `json:"name"`
+	State       string     `json:"state"`
+	Requests    int        `json:"requests"`
+	Failures    int        `json:"failures"`
+	FailureRate float64    `json:"failure_rate"`
+	OpenedAt    *time.Time `json:"opened_at,omitempty"`
+	RetryAt     *time.Time `json:"retry_at,omitempty"`
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]*Breaker{}
+)
+
+// New membuat breaker dan mendaftarkannya supaya statusnya bisa dilihat di /health/dependencies
+func New(name string, cfg Config) *Breaker {
+	if cfg.MaxProbes < 1 {
+		cfg.MaxProbes = 1
+	}
+	if cfg.MinRequests < 1 {
+		cfg.MinRequests = 1
+	}
+	b := &Breaker{name: name, cfg: cfg}
+
+	registryMu.Lock()
+	registry[name] = b
+	registryMu.Unlock()
+	return b
+}
+
+// Snapshots mengembalikan status semua breaker yang terdaftar, urut berdasarkan nama
+func Snapshots() []Snapshot {
+	registryMu.Lock()
+	breakers := make([]*Breaker, 0, len(registry))
+	for _, b := range registry {
+		breakers = append(breakers, b)
+	}
+	registryMu.Unlock()
+
+	snapshots := make([]Snapshot, 0, len(breakers))
+	for _, b := range breakers {
+		snapshots = append(snapshots, b.Snapshot())
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots
+}
+
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow harus dipanggil sebelum request keluar. Kalau nil, pemanggil wajib
+// melaporkan hasilnya lewat Success atau Failure.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && time.Since(b.openedAt) >= b.cfg.Cooldown {
+		b.transition(StateHalfOpen)
+	}
+
+	switch b.state {
+	case StateOpen:
+		return fmt.Errorf("%w: %s (retry after %s)", ErrOpen, b.name, b.openedAt.Add(b.cfg.Cooldown).Format(time.RFC3339))
+	case StateHalfOpen:
+		if b.probesInFlight+b.probeSuccesses >= b.cfg.MaxProbes {
+			return fmt.Errorf("%w: %s (half-open, probe in progress)", ErrOpen, b.name)
+		}
+		b.probesInFlight++
+	}
+	return nil
+}
+
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		if b.probesInFlight > 0 {
+			b.probesInFlight--
+		}
+		b.probeSuccesses++
+		if b.probeSuccesses >= b.cfg.MaxProbes {
+			b.transition(StateClosed)
+		}
+		return
+	}
+	b.record(true)
+}
+
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.transition(StateOpen)
+		return
+	}
+	b.record(false)
+
+	requests, failures := b.counts()
+	if b.state == StateClosed && requests >= b.cfg.MinRequests &&
+		float64(failures)/float64(requests) >= b.cfg.FailureRate {
+		b.transition(StateOpen)
+	}
+}
+
+// Release dipanggil kalau request yang sudah di-Allow dibatalkan pemanggil
+// (bukan kegagalan dependency), supaya slot probe half-open tidak tertahan
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen && b.probesInFlight > 0 {
+		b.probesInFlight--
+	}
+}
+
+// Reset memaksa breaker kembali ke closed dan mengosongkan window
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.transition(StateClosed)
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.prune()
+	requests, failures := b.counts()
+	snapshot := Snapshot{
+		Name:     b.name,
+		State:    b.state.String(),
+		Requests: requests,
+		Failures: failures,
+	}
+	if requests > 0 {
+		snapshot.FailureRate = float64(failures) / float64(requests)
+	}
+	if b.state != StateClosed {
+		openedAt := b.openedAt
+		retryAt := openedAt.Add(b.cfg.Cooldown)
+		snapshot.OpenedAt = &openedAt
+		snapshot.RetryAt = &retryAt
+	}
+	return snapshot
+}
+
+func (b *Breaker) transition(to State) {
+	if b.state == to {
+		return
+	}
+	log.Printf("Circuit breaker %s: %s -> %s", b.name, b.state, to)
+	b.state = to
+	b.probesInFlight = 0
+	b.probeSuccesses = 0
+
+	switch to {
+	case StateOpen:
+		b.openedAt = time.Now()
+	case StateClosed:
+		b.outcomes = nil
+	}
+}
+
+func (b *Breaker) record(success bool) {
+	b.outcomes = append(b.outcomes, outcome{at: time.Now(), success: success})
+	b.prune()
+}
+
+// prune membuang hasil yang sudah keluar dari sliding window
+func (b *Breaker) prune() {
+	cutoff := time.Now().Add(-b.cfg.Window)
+	i := 0
+	for i < len(b.outcomes) && b.outcomes[i].at.Before(cutoff) {
+		i++
+	}
+	b.outcomes = b.outcomes[i:]
+}
+
+func (b *Breaker) counts() (requests, failures int) {
+	for _, o := range b.outcomes {
+		if !o.success {
+			failures++
+		}
+	}
+	return len(b.outcomes), failures
+}
@@ -0,0 +1,220 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func testConfig() Config {
+	return Config{
+		Window:      time.Minute,
+		MinRequests: 4,
+		FailureRate: 0.5,
+		Cooldown:    time.Minute,
+		MaxProbes:   2,
+	}
+}
+
+// openBreaker membuat breaker yang sudah open
+func openBreaker(t *testing.T, name string) *Breaker {
+	t.Helper()
+	b := New(name, testConfig())
+	for i := 0; i < 4; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Allow while closed: %v", err)
+		}
+		b.Failure()
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("state = %s, want open", b.State())
+	}
+	return b
+}
+
+// expireCooldown memundurkan waktu open supaya Allow berikutnya pindah ke half-open
+func expireCooldown(b *Breaker) {
+	b.mu.Lock()
+	b.openedAt = time.Now().Add(-b.cfg.Cooldown)
+	b.mu.Unlock()
+}
+
+func TestBreakerOpensOnFailureRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		outcomes []bool // true = sukses
+		want     State
+	}{
+		{name: "below min requests", outcomes: []bool{false, false, false}, want: StateClosed},
+		{name: "rate below threshold", outcomes: []bool{true, true, true, false, true, false}, want: StateClosed},
+		{name: "rate at threshold", outcomes: []bool{true, false, true, false}, want: StateOpen},
+		{name: "all failures", outcomes: []bool{false, false, false, false}, want: StateOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := New("test-rate-"+tt.name, testConfig())
+			for _, success := range tt.outcomes {
+				if err := b.Allow(); err != nil {
+					t.Fatalf("Allow: %v", err)
+				}
+				if success {
+					b.Success()
+				} else {
+					b.Failure()
+				}
+			}
+			if got := b.State(); got != tt.want {
+				t.Errorf("state = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreakerRejectsWhileOpen(t *testing.T) {
+	b := openBreaker(t, "test-open")
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Allow while open = %v, want ErrOpen", err)
+	}
+
+	snapshot := b.Snapshot()
+	if snapshot.State != "open" || snapshot.OpenedAt == nil || snapshot.RetryAt == nil {
+		t.Errorf("snapshot = %+v, want open with opened_at & retry_at", snapshot)
+	}
+	if !snapshot.RetryAt.Equal(snapshot.OpenedAt.Add(time.Minute)) {
+		t.Errorf("retry_at = %v, want opened_at + cooldown", snapshot.RetryAt)
+	}
+}
+
+func TestBreakerHalfOpenProbes(t *testing.T) {
+	t.Run("all probes succeed closes", func(t *testing.T) {
+		b := openBreaker(t, "test-probe-success")
+		expireCooldown(b)
+
+		for i := 0; i < 2; i++ {
+			if err := b.Allow(); err != nil {
+				t.Fatalf("probe %d: %v", i+1, err)
+			}
+		}
+		if b.State() != StateHalfOpen {
+			t.Fatalf("state = %s, want half-open", b.State())
+		}
+		if err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("Allow beyond MaxProbes = %v, want ErrOpen", err)
+		}
+
+		b.Success()
+		if b.State() != StateHalfOpen {
+			t.Fatalf("state after 1/2 probes = %s, want half-open", b.State())
+		}
+		// Probe yang sudah sukses tetap memakai kuota
+		if err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("Allow with one probe in flight & one succeeded = %v, want ErrOpen", err)
+		}
+		b.Success()
+		if b.State() != StateClosed {
+			t.Fatalf("state after 2/2 probes = %s, want closed", b.State())
+		}
+		if s := b.Snapshot(); s.Requests != 0 || s.OpenedAt != nil {
+			t.Errorf("snapshot after close = %+v, want an empty window", s)
+		}
+	})
+
+	t.Run("probe failure reopens", func(t *testing.T) {
+		b := openBreaker(t, "test-probe-failure")
+		expireCooldown(b)
+
+		if err := b.Allow(); err != nil {
+			t.Fatalf("probe: %v", err)
+		}
+		b.Failure()
+		if b.State() != StateOpen {
+			t.Fatalf("state = %s, want open", b.State())
+		}
+		if err := b.Allow(); !errors.Is(err, ErrOpen) {
+			t.Fatalf("Allow right after reopening = %v, want ErrOpen (new cooldown)", err)
+		}
+	})
+
+	t.Run("release frees the probe slot", func(t *testing.T) {
+		b := openBreaker(t, "test-probe-release")
+		expireCooldown(b)
+
+		for i := 0; i < 2; i++ {
+			if err := b.Allow(); err != nil {
+				t.Fatalf("probe %d: %v", i+1, err)
+			}
+		}
+		b.Release()
+		if b.State() != StateHalfOpen {
+			t.Fatalf("Release changed state to %s", b.State())
+		}
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Allow after Release = %v, want a free probe slot", err)
+		}
+	})
+}
+
+func TestBreakerReleaseWhileClosedIsNoop(t *testing.T) {
+	b := New("test-release-closed", testConfig())
+	if err := b.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	b.Release()
+	if s := b.Snapshot(); s.State != "closed" || s.Requests != 0 {
+		t.Errorf("snapshot = %+v, want closed without recorded requests", s)
+	}
+}
+
+func TestBreakerSlidingWindow(t *testing.T) {
+	b := New("test-window", testConfig())
+	for i := 0; i < 3; i++ {
+		b.Allow()
+		b.Failure()
+	}
+	// Kegagalan lama keluar dari window dan tidak ikut dihitung
+	b.mu.Lock()
+	for i := range b.outcomes {
+		b.outcomes[i].at = time.Now().Add(-2 * time.Minute)
+	}
+	b.mu.Unlock()
+
+	b.Allow()
+	b.Failure()
+	if b.State() != StateClosed {
+		t.Fatalf("state = %s, want closed (old failures are outside the window)", b.State())
+	}
+	if s := b.Snapshot(); s.Requests != 1 || s.Failures != 1 || s.FailureRate != 1 {
+		t.Errorf("snapshot = %+v, want 1 request, 1 failure", s)
+	}
+}
+
+func TestBreakerReset(t *testing.T) {
+	b := openBreaker(t, "test-reset")
+	b.Reset()
+	if b.State() != StateClosed {
+		t.Fatalf("state = %s, want closed", b.State())
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow after Reset: %v", err)
+	}
+}
+
+func TestSnapshotsSortedByName(t *testing.T) {
+	New("test-registry-b", testConfig())
+	openBreaker(t, "test-registry-a")
+
+	var names []string
+	states := map[string]string{}
+	for _, s := range Snapshots() {
+		names = append(names, s.Name)
+		states[s.Name] = s.State
+	}
+	for i := 1; i < len(names); i++ {
+		if names[i-1] > names[i] {
+			t.Fatalf("snapshots not sorted: %v", names)
+		}
+	}
+	if states["test-registry-a"] != "open" || states["test-registry-b"] != "closed" {
+		t.Errorf("states = %v", states)
+	}
+}
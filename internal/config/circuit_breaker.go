@@ -0,0 +1,32 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+type CircuitBreakerConfig struct {
+	Window      time.Duration
+	MinRequests int
+	FailureRate float64
+	Cooldown    time.Duration
+	MaxProbes   int
+}
+
+var (
+	circuitBreakerConfig *CircuitBreakerConfig
+	circuitBreakerOnce   sync.Once
+)
+
+func LoadCircuitBreakerConfig() *CircuitBreakerConfig {
+	circuitBreakerOnce.Do(func() {
+		circuitBreakerConfig = &CircuitBreakerConfig{
+			Window:      getEnvDuration("CIRCUIT_BREAKER_WINDOW", time.Minute),
+			MinRequests: getEnvInt("CIRCUIT_BREAKER_MIN_REQUESTS", 5),
+			FailureRate: getEnvFloat("CIRCUIT_BREAKER_FAILURE_RATE", 0.5),
+			Cooldown:    getEnvDuration("CIRCUIT_BREAKER_COOLDOWN", time.Minute),
+			MaxProbes:   getEnvInt("CIRCUIT_BREAKER_MAX_PROBES", 1),
+		}
+	})
+	return circuitBreakerConfig
+}
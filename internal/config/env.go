@@ -0,0 +1,72 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// getEnv membaca env var string, fallback ke def kalau kosong
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// getEnvInt membaca env var sebagai int, fallback ke def kalau kosong/invalid
+func getEnvInt(key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, defaulting to %d", key, raw, def)
+		return def
+	}
+	return v
+}
+
+// getEnvFloat membaca env var sebagai float64, fallback ke def kalau kosong/invalid
+func getEnvFloat(key string, def float64) float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, defaulting to %v", key, raw, def)
+		return def
+	}
+	return v
+}
+
+// getEnvDuration membaca env var sebagai time.Duration (mis. "30s", "5m")
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, defaulting to %s", key, raw, def)
+		return def
+	}
+	return v
+}
+
+// getEnvBool membaca env var sebagai bool (true/false/1/0), fallback ke def kalau kosong/invalid
+func getEnvBool(key string, def bool) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, defaulting to %v", key, raw, def)
+		return def
+	}
+	return v
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"sync"
+)
+
+type EvaluationConfig struct {
+	// MaxRepairAttempts adalah berapa kali LLM diminta memperbaiki output yang gagal validasi
+	MaxRepairAttempts int
+	// ReuseResults menyalin hasil evaluasi sebelumnya untuk kombinasi CV, project
+	// report, job, rubric dan model yang sama, tanpa memanggil LLM lagi
+	ReuseResults bool
+}
+
+var (
+	evaluationConfig *EvaluationConfig
+	evaluationOnce   sync.Once
+)
+
+func LoadEvaluationConfig() *EvaluationConfig {
+	evaluationOnce.Do(func() {
+		evaluationConfig = &EvaluationConfig{
+			MaxRepairAttempts: getEnvInt("EVALUATION_MAX_REPAIR_ATTEMPTS", 2),
+			ReuseResults:      getEnvBool("EVALUATION_REUSE_RESULTS", false),
+		}
+	})
+	return evaluationConfig
+}
@@ -6,7 +6,9 @@ import (
 )
 
 type GeminiConfig struct {
-	APIKey string
+	APIKey         string
+	Model          string
+	EmbeddingModel string
 }
 
 var (
@@ -17,7 +19,9 @@ var (
 func LoadGeminiConfig() *GeminiConfig {
 	geminiOnce.Do(func() {
 		geminiConfig = &GeminiConfig{
-			APIKey: os.Getenv("GEMINI_API_KEY"),
+			APIKey:         os.Getenv("GEMINI_API_KEY"),
+			Model:          getEnv("GEMINI_MODEL", "gemini-2.5-flash"),
+			EmbeddingModel: getEnv("GEMINI_EMBEDDING_MODEL", "gemini-embedding-001"),
 		}
 	})
 	return geminiConfig
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"strings"
+	"sync"
+)
+
+type LLMConfig struct {
+	Provider          string // "gemini", "openrouter", "local"
+	FallbackProviders []string
+	EmbeddingProvider string
+}
+
+var (
+	llmConfig *LLMConfig
+	llmOnce   sync.Once
+)
+
+func LoadLLMConfig() *LLMConfig {
+	llmOnce.Do(func() {
+		provider := getEnv("LLM_PROVIDER", "gemini")
+		var fallbacks []string
+		for _, name := range strings.Split(getEnv("LLM_FALLBACK_PROVIDERS", ""), ",") {
+			if name = strings.TrimSpace(name); name != "" && name != provider {
+				fallbacks = append(fallbacks, name)
+			}
+		}
+		llmConfig = &LLMConfig{
+			Provider:          provider,
+			FallbackProviders: fallbacks,
+			EmbeddingProvider: getEnv("EMBEDDING_PROVIDER", provider),
+		}
+	})
+	return llmConfig
+}
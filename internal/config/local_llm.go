@@ -0,0 +1,31 @@
+package config
+
+import (
+	"os"
+	"sync"
+)
+
+// LocalLLMConfig untuk endpoint OpenAI-compatible lokal (Ollama, llama.cpp server, dsb.)
+type LocalLLMConfig struct {
+	BaseURL        string
+	APIKey         string
+	Model          string
+	EmbeddingModel string
+}
+
+var (
+	localLLMConfig *LocalLLMConfig
+	localLLMOnce   sync.Once
+)
+
+func LoadLocalLLMConfig() *LocalLLMConfig {
+	localLLMOnce.Do(func() {
+		localLLMConfig = &LocalLLMConfig{
+			BaseURL:        getEnv("LOCAL_LLM_BASE_URL", "http://localhost:11434/v1"),
+			APIKey:         os.Getenv("LOCAL_LLM_API_KEY"),
+			Model:          getEnv("LOCAL_LLM_MODEL", "llama3.1"),
+			EmbeddingModel: getEnv("LOCAL_LLM_EMBEDDING_MODEL", "nomic-embed-text"),
+		}
+	})
+	return localLLMConfig
+}
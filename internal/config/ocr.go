@@ -0,0 +1,67 @@
+package config
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+type OCRConfig struct {
+	// Workers adalah jumlah halaman satu dokumen yang di-render & di-OCR bersamaan
+	Workers int
+	// CPUBudget adalah jumlah core yang boleh dipakai semua proses tesseract di server
+	CPUBudget int
+	// ThreadsPerProcess dikirim ke tesseract sebagai OMP_THREAD_LIMIT
+	ThreadsPerProcess int
+	// Timeout membatasi lama ekstraksi satu dokumen
+	Timeout time.Duration
+	// Languages adalah bahasa tesseract yang terpasang, format "eng+ind"
+	Languages string
+	// AutoDetectLanguage mendeteksi bahasa dokumen dari sampel halaman pertama
+	// lalu meng-OCR sisa halaman hanya dengan bahasa tersebut
+	AutoDetectLanguage bool
+	// DPI adalah resolusi render halaman PDF untuk OCR
+	DPI float64
+	// Deskew & Binarize mengaktifkan preprocessing gambar sebelum OCR
+	Deskew   bool
+	Binarize bool
+	// MinQuality adalah skor kualitas dokumen (0-1) minimal sebelum ditandai low quality
+	MinQuality float64
+	// RejectLowQuality menggagalkan task kalau kualitas ekstraksi di bawah MinQuality
+	RejectLowQuality bool
+}
+
+// LanguageList memecah Languages menjadi daftar kode bahasa tesseract
+func (c *OCRConfig) LanguageList() []string {
+	return strings.FieldsFunc(c.Languages, func(r rune) bool { return r == '+' || r == ',' })
+}
+
+// MaxProcesses adalah jumlah proses tesseract yang boleh jalan bersamaan
+func (c *OCRConfig) MaxProcesses() int {
+	return max(1, c.CPUBudget/c.ThreadsPerProcess)
+}
+
+var (
+	ocrConfig *OCRConfig
+	ocrOnce   sync.Once
+)
+
+func LoadOCRConfig() *OCRConfig {
+	ocrOnce.Do(func() {
+		ocrConfig = &OCRConfig{
+			Workers:            max(1, getEnvInt("OCR_WORKERS", 4)),
+			CPUBudget:          max(1, getEnvInt("OCR_CPU_BUDGET", runtime.NumCPU())),
+			ThreadsPerProcess:  max(1, getEnvInt("OCR_THREADS_PER_PROCESS", 1)),
+			Timeout:            getEnvDuration("OCR_TIMEOUT", 3*time.Minute),
+			Languages:          getEnv("OCR_LANGUAGES", "eng+ind"),
+			AutoDetectLanguage: getEnvBool("OCR_AUTO_DETECT_LANGUAGE", true),
+			DPI:                getEnvFloat("OCR_DPI", 300),
+			Deskew:             getEnvBool("OCR_DESKEW", true),
+			Binarize:           getEnvBool("OCR_BINARIZE", true),
+			MinQuality:         getEnvFloat("OCR_MIN_QUALITY", 0.6),
+			RejectLowQuality:   getEnvBool("OCR_REJECT_LOW_QUALITY", false),
+		}
+	})
+	return ocrConfig
+}
@@ -6,7 +6,10 @@ import (
 )
 
 type OpenRouterConfig struct {
-	APIKey string
+	APIKey         string
+	BaseURL        string
+	Model          string
+	EmbeddingModel string
 }
 
 var (
@@ -17,7 +20,10 @@ var (
 func LoadOpenRouterConfig() *OpenRouterConfig {
 	openRouterOnce.Do(func() {
 		openRouterConfig = &OpenRouterConfig{
-			APIKey: os.Getenv("OPENROUTER_API_KEY"),
+			APIKey:         os.Getenv("OPENROUTER_API_KEY"),
+			BaseURL:        getEnv("OPENROUTER_BASE_URL", "https://openrouter.ai/api/v1"),
+			Model:          getEnv("OPENROUTER_MODEL", "openai/gpt-4o-mini"),
+			EmbeddingModel: os.Getenv("OPENROUTER_EMBEDDING_MODEL"),
 		}
 	})
 	return openRouterConfig
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+type QueueConfig struct {
+	PollInterval  time.Duration
+	LeaseDuration time.Duration
+	MaxAttempts   int
+	RetryDelay    time.Duration
+}
+
+var (
+	queueConfig *QueueConfig
+	queueOnce   sync.Once
+)
+
+// minLeaseDuration menjaga heartbeat (setiap LeaseDuration/3) tetap berjalan
+// dan sempat memperpanjang lease sebelum habis
+const minLeaseDuration = 3 * time.Second
+
+func LoadQueueConfig() *QueueConfig {
+	queueOnce.Do(func() {
+		queueConfig = &QueueConfig{
+			PollInterval:  getEnvDuration("QUEUE_POLL_INTERVAL", 2*time.Second),
+			LeaseDuration: getEnvDuration("QUEUE_LEASE_DURATION", 5*time.Minute),
+			MaxAttempts:   getEnvInt("QUEUE_MAX_ATTEMPTS", 3),
+			RetryDelay:    getEnvDuration("QUEUE_RETRY_DELAY", 30*time.Second),
+		}
+		// Nilai yang membuat ticker panic atau menggagalkan semua job dikoreksi
+		if queueConfig.PollInterval <= 0 {
+			queueConfig.PollInterval = 2 * time.Second
+		}
+		if queueConfig.LeaseDuration < minLeaseDuration {
+			queueConfig.LeaseDuration = minLeaseDuration
+		}
+		if queueConfig.MaxAttempts < 1 {
+			queueConfig.MaxAttempts = 1
+		}
+		if queueConfig.RetryDelay < 0 {
+			queueConfig.RetryDelay = 0
+		}
+	})
+	return queueConfig
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"sync"
+)
+
+type SkillsConfig struct {
+	// TaxonomyFile adalah file JSON taxonomy skill; kosong = taxonomy bawaan
+	TaxonomyFile string
+}
+
+var (
+	skillsConfig *SkillsConfig
+	skillsOnce   sync.Once
+)
+
+func LoadSkillsConfig() *SkillsConfig {
+	skillsOnce.Do(func() {
+		skillsConfig = &SkillsConfig{
+			TaxonomyFile: getEnv("SKILLS_TAXONOMY_FILE", ""),
+		}
+	})
+	return skillsConfig
+}
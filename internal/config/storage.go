@@ -0,0 +1,41 @@
+package config
+
+import (
+	"sync"
+)
+
+type StorageConfig struct {
+	// Driver adalah backend penyimpanan file upload: "local" atau "s3"
+	Driver string
+	// LocalDir adalah root folder untuk driver local
+	LocalDir string
+	// S3* dipakai driver s3 (AWS S3, MinIO, atau storage lain yang kompatibel)
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	// S3PathStyle memakai URL endpoint/bucket/key (wajib untuk MinIO)
+	S3PathStyle bool
+}
+
+var (
+	storageConfig *StorageConfig
+	storageOnce   sync.Once
+)
+
+func LoadStorageConfig() *StorageConfig {
+	storageOnce.Do(func() {
+		storageConfig = &StorageConfig{
+			Driver:      getEnv("STORAGE_DRIVER", "local"),
+			LocalDir:    getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			S3Endpoint:  getEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			S3Region:    getEnv("S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("S3_BUCKET", ""),
+			S3AccessKey: getEnv("S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("S3_SECRET_KEY", ""),
+			S3PathStyle: getEnvBool("S3_USE_PATH_STYLE", true),
+		}
+	})
+	return storageConfig
+}
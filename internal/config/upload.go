@@ -0,0 +1,34 @@
+package config
+
+import (
+	"sync"
+)
+
+type UploadConfig struct {
+	// MaxFileSize adalah ukuran maksimal satu file upload (byte)
+	MaxFileSize int64
+	// MaxPages adalah jumlah halaman PDF maksimal
+	MaxPages int
+	// MaxImagePixels membatasi ukuran gambar upload dan halaman PDF yang
+	// di-render pada OCR_DPI (lebar x tinggi), mencegah decompression bomb
+	MaxImagePixels int
+	// MaxUncompressedSize membatasi total isi DOCX/ODT setelah di-unzip (byte)
+	MaxUncompressedSize int64
+}
+
+var (
+	uploadConfig *UploadConfig
+	uploadOnce   sync.Once
+)
+
+func LoadUploadConfig() *UploadConfig {
+	uploadOnce.Do(func() {
+		uploadConfig = &UploadConfig{
+			MaxFileSize:         int64(max(1, getEnvInt("UPLOAD_MAX_FILE_SIZE", 5*1024*1024))),
+			MaxPages:            max(1, getEnvInt("UPLOAD_MAX_PAGES", 30)),
+			MaxImagePixels:      max(1, getEnvInt("UPLOAD_MAX_IMAGE_PIXELS", 40_000_000)),
+			MaxUncompressedSize: int64(max(1, getEnvInt("UPLOAD_MAX_UNCOMPRESSED_SIZE", 50*1024*1024))),
+		}
+	})
+	return uploadConfig
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"sync"
+	"time"
+)
+
+type WorkerConfig struct {
+	Concurrency  int
+	MaxBacklog   int
+	TaskInterval time.Duration
+	RetryAfter   time.Duration
+}
+
+var (
+	workerConfig *WorkerConfig
+	workerOnce   sync.Once
+)
+
+func LoadWorkerConfig() *WorkerConfig {
+	workerOnce.Do(func() {
+		concurrency := getEnvInt("WORKER_CONCURRENCY", 2)
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		workerConfig = &WorkerConfig{
+			Concurrency:  concurrency,
+			MaxBacklog:   getEnvInt("WORKER_MAX_BACKLOG", 100),
+			TaskInterval: getEnvDuration("WORKER_TASK_INTERVAL", 4*time.Second),
+			RetryAfter:   getEnvDuration("WORKER_RETRY_AFTER", 30*time.Second),
+		}
+	})
+	return workerConfig
+}
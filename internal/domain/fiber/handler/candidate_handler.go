@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/dto"
+	"github.com/fadilmartias/cv-analyzer/internal/usecase"
+	"github.com/fadilmartias/cv-analyzer/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CandidateHandler struct {
+	uc *usecase.CandidateUsecase
+}
+
+func NewCandidateHandler(uc *usecase.CandidateUsecase) *CandidateHandler {
+	return &CandidateHandler{uc: uc}
+}
+
+func (h *CandidateHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/candidates", h.List)
+	app.Get("/candidates/:id", h.Get)
+	app.Get("/candidates/:id/evaluations", h.Evaluations)
+	app.Get("/candidates/:id/compare", h.Compare)
+}
+
+func (h *CandidateHandler) List(c *fiber.Ctx) error {
+	page, pageSize := paginationParams(c)
+
+	filter := usecase.CandidateFilter{
+		Name:  strings.TrimSpace(c.Query("name")),
+		Email: strings.TrimSpace(c.Query("email")),
+		Phone: strings.TrimSpace(c.Query("phone")),
+	}
+	candidates, pagination, err := h.uc.List(filter, page, pageSize)
+	if err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Message: "failed to get candidates",
+		}, err)
+	}
+
+	data := make([]dto.CandidateDTO, 0, len(candidates))
+	for i := range candidates {
+		data = append(data, toCandidateDTO(&candidates[i]))
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message:    "Success get candidates",
+		Data:       data,
+		Pagination: pagination,
+	})
+}
+
+func (h *CandidateHandler) Get(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return h.candidateError(c, usecase.ErrCandidateNotFound, "")
+	}
+
+	candidate, err := h.uc.Get(id)
+	if err != nil {
+		return h.candidateError(c, err, "failed to get candidate")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success get candidate",
+		Data:    toCandidateDTO(candidate),
+	})
+}
+
+// Evaluations mengembalikan riwayat evaluasi candidate dari yang terlama,
+// dengan perubahan skor terhadap evaluasi sebelumnya untuk job yang sama
+func (h *CandidateHandler) Evaluations(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return h.candidateError(c, usecase.ErrCandidateNotFound, "")
+	}
+
+	history, err := h.uc.Evaluations(id)
+	if err != nil {
+		return h.candidateError(c, err, "failed to get candidate evaluations")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success get candidate evaluations",
+		Data:    history,
+	})
+}
+
+// Compare membandingkan dua evaluasi candidate (query from & to); tanpa query
+// dua evaluasi selesai terakhir yang dibandingkan
+func (h *CandidateHandler) Compare(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return h.candidateError(c, usecase.ErrCandidateNotFound, "")
+	}
+
+	var ids [2]*uuid.UUID
+	for i, name := range []string{"from", "to"} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(raw)
+		if err != nil {
+			return util.ErrorResponse(c, util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: "invalid " + name,
+			}, err)
+		}
+		ids[i] = &parsed
+	}
+
+	comparison, err := h.uc.Compare(id, ids[0], ids[1])
+	if err != nil {
+		return h.candidateError(c, err, "failed to compare evaluations")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success compare evaluations",
+		Data:    comparison,
+	})
+}
+
+func (h *CandidateHandler) candidateError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, usecase.ErrCandidateNotFound):
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusNotFound,
+			Message: "candidate not found",
+		})
+	case errors.Is(err, usecase.ErrEvaluationNotFound):
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusNotFound,
+			Message: "completed evaluation not found for this candidate",
+		})
+	case errors.Is(err, usecase.ErrNothingToCompare):
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusUnprocessableEntity,
+			Message: err.Error(),
+		})
+	}
+	return util.ErrorResponse(c, util.ErrorResponseFormat{
+		Message: message,
+	}, err)
+}
+
+func toCandidateDTO(candidate *usecase.CandidateSummary) dto.CandidateDTO {
+	return dto.CandidateDTO{
+		ID:               candidate.ID,
+		Name:             candidate.Name,
+		Email:            candidate.Email,
+		Phone:            candidate.Phone,
+		EvaluationCount:  candidate.EvaluationCount,
+		LastEvaluationAt: candidate.LastEvaluationAt,
+		CreatedAt:        candidate.CreatedAt,
+		UpdatedAt:        candidate.UpdatedAt,
+	}
+}
@@ -1,67 +1,141 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
-	"time"
 
+	"github.com/fadilmartias/cv-analyzer/internal/config"
 	"github.com/fadilmartias/cv-analyzer/internal/dto"
-	"github.com/fadilmartias/cv-analyzer/internal/middleware"
+	"github.com/fadilmartias/cv-analyzer/internal/extractor"
 	"github.com/fadilmartias/cv-analyzer/internal/model"
 	"github.com/fadilmartias/cv-analyzer/internal/usecase"
 	"github.com/fadilmartias/cv-analyzer/internal/util"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type EvaluateHandler struct {
-	uc *usecase.EvaluationUsecase
+	uc         *usecase.EvaluationUsecase
+	documentUc *usecase.DocumentUsecase
 }
 
-func NewEvaluateHandler(uc *usecase.EvaluationUsecase) *EvaluateHandler {
-	return &EvaluateHandler{uc: uc}
+func NewEvaluateHandler(uc *usecase.EvaluationUsecase, documentUc *usecase.DocumentUsecase) *EvaluateHandler {
+	return &EvaluateHandler{uc: uc, documentUc: documentUc}
 }
 
 func (h *EvaluateHandler) RegisterRoutes(app *fiber.App) {
-	app.Post("/evaluate", middleware.RateLimiter(1, 4*time.Second), h.Evaluate)
+	app.Post("/evaluate", h.Evaluate)
+	app.Get("/results", h.List)
 	app.Get("/result/:id", h.Result)
 	app.Get("/test", h.Test)
 	app.Get("/create-job-embedding", h.CreateJobEmbedding)
 }
 
 func (h *EvaluateHandler) Evaluate(c *fiber.Ctx) error {
-	cvContent, err := h.processFile(c, "cv", "./uploads/cv/")
+	// Cek backlog sebelum menyimpan file supaya request ditolak cepat saat antrian penuh
+	if err := h.uc.CheckBacklog(); err != nil {
+		return h.submitError(c, err)
+	}
+
+	// job_id opsional: kalau diisi, evaluasi hanya terhadap job tersebut
+	var jobID *uuid.UUID
+	if raw := strings.TrimSpace(c.FormValue("job_id")); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return util.ErrorResponse(c, util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: "invalid job_id",
+			}, err)
+		}
+		if _, err := h.uc.FindJob(id.String()); err != nil {
+			if errors.Is(err, usecase.ErrJobNotFound) {
+				return util.ErrorResponse(c, util.ErrorResponseFormat{
+					Code:    fiber.StatusNotFound,
+					Message: "job not found",
+				})
+			}
+			return util.ErrorResponse(c, util.ErrorResponseFormat{
+				Message: "failed to get job",
+			}, err)
+		}
+		jobID = &id
+	}
+
+	// lang opsional: bahasa OCR, harus termasuk OCR_LANGUAGES yang terpasang
+	lang, err := parseOCRLanguages(c.FormValue("lang"))
+	if err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid lang",
+			Details: map[string]string{"lang": err.Error()},
+		})
+	}
+
+	// Kedua file divalidasi dulu sebelum ada yang disimpan, supaya upload yang
+	// ditolak tidak meninggalkan dokumen. File hanya disimpan di sini; ekstraksi
+	// teks dijalankan worker di background.
+	cvUpload, err := h.stageUpload(c, "cv")
 	if err != nil {
-		return err
+		return requestErrorResponse(c, err, "cannot read cv file")
 	}
+	defer cvUpload.Close()
 
-	reportContent, err := h.processFile(c, "project_report", "./uploads/project_report/")
+	reportUpload, err := h.stageUpload(c, "project_report")
 	if err != nil {
-		return err
+		return requestErrorResponse(c, err, "cannot read project_report file")
 	}
+	defer reportUpload.Close()
 
-	log.Println("CV Content:", cvContent)
-	log.Println("Report Content:", reportContent)
+	cv, err := h.documentUc.Store(c.UserContext(), cvUpload)
+	if err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Message: "cannot save cv file",
+		}, err)
+	}
+	report, err := h.documentUc.Store(c.UserContext(), reportUpload)
+	if err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Message: "cannot save project_report file",
+		}, err)
+	}
 
 	task := model.EvaluationTask{
-		CV:     cvContent,
-		Report: reportContent,
+		JobID:            jobID,
+		CVDocumentID:     &cv.ID,
+		ReportDocumentID: &report.ID,
+		OCRLanguages:     lang,
 	}
 
 	id, err := h.uc.Submit(task)
 	if err != nil {
-		return util.ErrorResponse(c, util.ErrorResponseFormat{
-			Message: "failed to submit evaluation",
-		}, err)
+		return h.submitError(c, err)
 	}
 
 	return util.SuccessResponse(c, util.SuccessResponseFormat{
 		Message: "Success submit evaluation",
-		Data:    fiber.Map{"id": id, "status": "processing"},
+		Data:    fiber.Map{"id": id, "status": model.TaskStatusExtracting},
 	})
 }
 
+func (h *EvaluateHandler) submitError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, usecase.ErrBacklogFull) {
+		retryAfter := config.LoadWorkerConfig().RetryAfter
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(retryAfter.Seconds())))
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusServiceUnavailable,
+			Message: "evaluation queue is full, please retry later",
+		}, err)
+	}
+	return util.ErrorResponse(c, util.ErrorResponseFormat{
+		Message: "failed to submit evaluation",
+	}, err)
+}
+
 func (h *EvaluateHandler) CreateJobEmbedding(c *fiber.Ctx) error {
 	if err := h.uc.CreateJobEmbedding(); err != nil {
 		return util.ErrorResponse(c, util.ErrorResponseFormat{
@@ -73,46 +147,132 @@ func (h *EvaluateHandler) CreateJobEmbedding(c *fiber.Ctx) error {
 	})
 }
 
-func (h *EvaluateHandler) processFile(c *fiber.Ctx, fieldName, uploadDir string) (string, error) {
+// parseOCRLanguages memvalidasi lang ("ind", "eng+ind") terhadap OCR_LANGUAGES
+func parseOCRLanguages(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+	installed := config.LoadOCRConfig().LanguageList()
+	langs := strings.Split(raw, "+")
+	for _, lang := range langs {
+		if !slices.Contains(installed, lang) {
+			return "", fmt.Errorf("%q is not available, use one or more of %s joined with +", lang, strings.Join(installed, ", "))
+		}
+	}
+	return strings.Join(langs, "+"), nil
+}
+
+// stageUpload membaca file upload ke file temp dan memastikan formatnya
+// didukung (dideteksi dari isi file). Nama file dari client hanya dicatat,
+// tidak dipakai sebagai nama file di storage. Upload yang ditolak dikembalikan
+// sebagai requestError.
+func (h *EvaluateHandler) stageUpload(c *fiber.Ctx, fieldName string) (*usecase.StagedUpload, error) {
 	file, err := c.FormFile(fieldName)
 	if err != nil {
-		return "", util.ErrorResponse(c, util.ErrorResponseFormat{
-			Message: fmt.Sprintf("%s file is required", fieldName),
-		}, err)
+		return nil, &requestError{
+			format: util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: fmt.Sprintf("%s file is required", fieldName),
+			},
+			err: err,
+		}
 	}
 
-	fileSize := file.Size
-	if fileSize > 5*1024*1024 {
-		return "", util.ErrorResponse(c, util.ErrorResponseFormat{
-			Message: fmt.Sprintf("%s file size is too large (max 5MB)", fieldName),
-		}, nil)
+	maxSize := config.LoadUploadConfig().MaxFileSize
+	if file.Size > maxSize {
+		return nil, &requestError{format: util.ErrorResponseFormat{
+			Code:    fiber.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("%s file size is too large (max %s)", fieldName, formatBytes(maxSize)),
+		}}
 	}
 
-	savePath := filepath.Join(uploadDir, file.Filename)
-	if err := c.SaveFile(file, savePath); err != nil {
-		return "", util.ErrorResponse(c, util.ErrorResponseFormat{
-			Message: fmt.Sprintf("cannot save %s file", fieldName),
-		}, err)
+	f, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s file: %w", fieldName, err)
 	}
+	defer f.Close()
 
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	var content string
-	switch ext {
-	case ".pdf":
-		content, err = util.ExtractPDFOCR(savePath)
-	default:
-		return "", util.ErrorResponse(c, util.ErrorResponseFormat{
-			Message: fmt.Sprintf("unsupported %s file type", fieldName),
-		}, nil)
+	staged, err := h.documentUc.Stage(file.Filename, f)
+	if errors.Is(err, extractor.ErrUnsupportedType) {
+		return nil, &requestError{
+			format: util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: fmt.Sprintf("unsupported %s file type", fieldName),
+			},
+			err: err,
+		}
+	}
+	if errors.Is(err, extractor.ErrInvalidFile) {
+		return nil, &requestError{
+			format: util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: fmt.Sprintf("invalid %s file", fieldName),
+				Details: map[string]string{fieldName: err.Error()},
+			},
+			err: err,
+		}
 	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s file: %w", fieldName, err)
+	}
+	return staged, nil
+}
+
+// List mendukung filter ?status=, ?job_id=, ?name=, ?email=, ?location=,
+// ?language= dan ?skills=go,postgresql (semua skill harus dimiliki kandidat)
+func (h *EvaluateHandler) List(c *fiber.Ctx) error {
+	page, pageSize := paginationParams(c)
 
+	filter := usecase.ResultFilter{
+		Status:   strings.TrimSpace(c.Query("status")),
+		Name:     strings.TrimSpace(c.Query("name")),
+		Email:    strings.TrimSpace(c.Query("email")),
+		Location: strings.TrimSpace(c.Query("location")),
+		Language: strings.TrimSpace(c.Query("language")),
+	}
+	if raw := c.Query("job_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return util.ErrorResponse(c, util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: "invalid job_id",
+			}, err)
+		}
+		filter.JobID = &id
+	}
+	if raw := c.Query("candidate_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return util.ErrorResponse(c, util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: "invalid candidate_id",
+			}, err)
+		}
+		filter.CandidateID = &id
+	}
+	for _, skill := range strings.Split(c.Query("skills"), ",") {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			filter.Skills = append(filter.Skills, skill)
+		}
+	}
+
+	tasks, pagination, err := h.uc.ListResults(filter, page, pageSize)
 	if err != nil {
-		return "", util.ErrorResponse(c, util.ErrorResponseFormat{
-			Message: fmt.Sprintf("failed to extract %s text", fieldName),
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Message: "failed to get evaluation results",
 		}, err)
 	}
 
-	return content, nil
+	data := make([]dto.EvaluationSummaryDTO, 0, len(tasks))
+	for _, task := range tasks {
+		data = append(data, dto.NewEvaluationSummaryDTO(task))
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message:    "Success get evaluation results",
+		Data:       data,
+		Pagination: pagination,
+	})
 }
 
 func (h *EvaluateHandler) Result(c *fiber.Ctx) error {
@@ -123,17 +283,86 @@ func (h *EvaluateHandler) Result(c *fiber.Ctx) error {
 			Message: "job not found",
 		}, nil)
 	}
+	contextJobs := []model.ContextJob{}
+	if job.ContextJobs != "" {
+		if err := json.Unmarshal([]byte(job.ContextJobs), &contextJobs); err != nil {
+			log.Printf("Invalid context_jobs for task %s: %v", job.ID, err)
+		}
+	}
+	var scoreDetails *dto.ScoreDetails
+	if job.ScoreDetails != "" && job.ScoreDetails != "{}" {
+		scoreDetails = &dto.ScoreDetails{}
+		if err := json.Unmarshal([]byte(job.ScoreDetails), scoreDetails); err != nil {
+			log.Printf("Invalid score_details for task %s: %v", job.ID, err)
+			scoreDetails = nil
+		}
+	}
+	statusHistory := job.StatusHistory
+	if statusHistory == nil {
+		statusHistory = []model.StatusChange{}
+	}
+	stages := []dto.EvaluationStageDTO{}
+	if records, err := h.uc.GetStages(job); err != nil {
+		log.Printf("Get stages for task %s failed: %v", job.ID, err)
+	} else {
+		for _, stage := range records {
+			stages = append(stages, dto.EvaluationStageDTO{
+				Stage:     stage.Stage,
+				Status:    stage.Status,
+				Attempts:  stage.Attempts,
+				Provider:  stage.Provider,
+				Model:     stage.Model,
+				Error:     stage.Error,
+				UpdatedAt: stage.UpdatedAt,
+			})
+		}
+	}
+	var cvDocument, reportDocument *dto.DocumentDTO
+	if docs, err := h.documentUc.GetMany(job.CVDocumentID, job.ReportDocumentID); err != nil {
+		log.Printf("Get documents for task %s failed: %v", job.ID, err)
+	} else {
+		if job.CVDocumentID != nil {
+			if doc, ok := docs[*job.CVDocumentID]; ok {
+				cvDocument = dto.NewDocumentDTO(doc)
+			}
+		}
+		if job.ReportDocumentID != nil {
+			if doc, ok := docs[*job.ReportDocumentID]; ok {
+				reportDocument = dto.NewDocumentDTO(doc)
+			}
+		}
+	}
 	data := dto.EvaluationTaskDTO{
-		ID:              job.ID,
-		Status:          job.Status,
-		CvMatchRate:     job.CvMatchRate,
-		CvFeedback:      job.CvFeedback,
-		ProjectScore:    job.ProjectScore,
-		ProjectFeedback: job.ProjectFeedback,
-		OverallSummary:  job.OverallSummary,
-		Breakdown:       job.Breakdown,
-		CreatedAt:       job.CreatedAt,
-		UpdatedAt:       job.UpdatedAt,
+		ID:                job.ID,
+		JobID:             job.JobID,
+		CandidateID:       job.CandidateID,
+		RubricID:          job.RubricID,
+		Status:            job.Status,
+		Language:          job.Language,
+		Profile:           job.Profile,
+		Timeline:          job.Timeline,
+		SkillGap:          job.SkillGap,
+		StatusHistory:     statusHistory,
+		CvMatchRate:       job.CvMatchRate,
+		CvFeedback:        job.CvFeedback,
+		ProjectScore:      job.ProjectScore,
+		ProjectFeedback:   job.ProjectFeedback,
+		OverallSummary:    job.OverallSummary,
+		Breakdown:         job.Breakdown,
+		ScoreDetails:      scoreDetails,
+		ContextJobs:       contextJobs,
+		CVDocument:        cvDocument,
+		ReportDocument:    reportDocument,
+		Extraction:        rawJSON(job.Extraction),
+		ExtractionQuality: job.ExtractionQuality,
+		LowQuality:        job.LowQuality,
+		Stages:            stages,
+		ReusedFromID:      job.ReusedFromID,
+		Provider:          job.Provider,
+		Model:             job.Model,
+		Error:             job.Error,
+		CreatedAt:         job.CreatedAt,
+		UpdatedAt:         job.UpdatedAt,
 	}
 	return util.SuccessResponse(c, util.SuccessResponseFormat{
 		Message: "Success get evaluation result",
@@ -153,3 +382,23 @@ func (h *EvaluateHandler) Test(c *fiber.Ctx) error {
 		Data:    gemini,
 	})
 }
+
+// formatBytes menampilkan ukuran file untuk pesan error, mis. "5MB"
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1024*1024 && size%(1024*1024) == 0:
+		return fmt.Sprintf("%dMB", size/(1024*1024))
+	case size >= 1024 && size%1024 == 0:
+		return fmt.Sprintf("%dKB", size/1024)
+	default:
+		return fmt.Sprintf("%d bytes", size)
+	}
+}
+
+// rawJSON meneruskan kolom jsonb apa adanya; nilai kosong dikirim sebagai {}
+func rawJSON(value string) json.RawMessage {
+	if value == "" {
+		return json.RawMessage("{}")
+	}
+	return json.RawMessage(value)
+}
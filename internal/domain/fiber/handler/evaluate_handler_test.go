@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rc4"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fadilmartias/cv-analyzer/internal/usecase"
+	"github.com/gofiber/fiber/v2"
+)
+
+// uploadApp menjalankan stageUpload di route test supaya penolakan upload bisa
+// dicek tanpa database & storage
+func uploadApp() *fiber.App {
+	h := NewEvaluateHandler(nil, usecase.NewDocumentUsecase(nil, nil, nil))
+	app := fiber.New()
+	app.Post("/upload", func(c *fiber.Ctx) error {
+		staged, err := h.stageUpload(c, "cv")
+		if err != nil {
+			return requestErrorResponse(c, err, "cannot read cv file")
+		}
+		defer staged.Close()
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+func TestStageUploadRejections(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00")
+	tests := []struct {
+		name       string
+		filename   string
+		content    []byte
+		wantStatus int
+		wantDetail string
+	}{
+		{name: "missing file", wantStatus: fiber.StatusBadRequest},
+		{name: "unsupported type", filename: "cv.zip", content: []byte("PK\x05\x06" + strings.Repeat("\x00", 18)), wantStatus: fiber.StatusBadRequest},
+		{name: "type mismatch", filename: "cv.pdf", content: png, wantStatus: fiber.StatusBadRequest, wantDetail: "does not match its extension"},
+		{name: "encrypted pdf", filename: "cv.pdf", content: encryptedPDF(t), wantStatus: fiber.StatusBadRequest, wantDetail: "encrypted"},
+		{name: "valid text", filename: "cv.txt", content: []byte("Jane Doe\nBackend Engineer with 5 years of Go experience.\n"), wantStatus: fiber.StatusNoContent},
+	}
+
+	app := uploadApp()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			if tt.filename != "" {
+				part, err := form.CreateFormFile("cv", tt.filename)
+				if err != nil {
+					t.Fatal(err)
+				}
+				part.Write(tt.content)
+			}
+			form.Close()
+
+			req := httptest.NewRequest(fiber.MethodPost, "/upload", &body)
+			req.Header.Set(fiber.HeaderContentType, form.FormDataContentType())
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.wantStatus, raw)
+			}
+			if tt.wantDetail == "" {
+				return
+			}
+			var parsed struct {
+				Details map[string]string `json:"details"`
+			}
+			if err := json.Unmarshal(raw, &parsed); err != nil {
+				t.Fatalf("decode response %s: %v", raw, err)
+			}
+			if !strings.Contains(parsed.Details["cv"], tt.wantDetail) {
+				t.Errorf("details = %v, want cv detail containing %q", parsed.Details, tt.wantDetail)
+			}
+		})
+	}
+}
+
+// encryptedPDF membuat PDF satu halaman yang dilindungi user password dengan
+// standard security handler revisi 2 (RC4 40-bit)
+func encryptedPDF(t *testing.T) []byte {
+	t.Helper()
+	padding := []byte("\x28\xbf\x4e\x5e\x4e\x75\x8a\x41\x64\x00\x4e\x56\xff\xfa\x01\x08\x2e\x2e\x00\xb6\xd0\x68\x3e\x80\x2f\x0c\xa9\xfe\x64\x53\x69\x7a")
+	pad := func(password string) []byte {
+		return append([]byte(password), padding...)[:32]
+	}
+	encrypt := func(key, data []byte) []byte {
+		cipher, err := rc4.NewCipher(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make([]byte, len(data))
+		cipher.XORKeyStream(out, data)
+		return out
+	}
+
+	permissions := int32(-4)
+	id := []byte("0123456789abcdef")
+	ownerKey := md5.Sum(pad("owner"))
+	owner := encrypt(ownerKey[:5], pad("user"))
+
+	seed := append(pad("user"), owner...)
+	seed = binary.LittleEndian.AppendUint32(seed, uint32(permissions))
+	seed = append(seed, id...)
+	fileKey := md5.Sum(seed)
+	user := encrypt(fileKey[:5], padding)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		fmt.Sprintf("<< /Filter /Standard /V 1 /R 2 /O <%x> /U <%x> /P %d >>", owner, user, permissions),
+	}
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R /Encrypt 4 0 R /ID [<%x> <%x>] >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, id, id, xref)
+	return pdf.Bytes()
+}
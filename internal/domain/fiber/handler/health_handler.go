@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/fadilmartias/cv-analyzer/internal/circuitbreaker"
+	"github.com/fadilmartias/cv-analyzer/internal/util"
+	"github.com/gofiber/fiber/v2"
+)
+
+type HealthHandler struct{}
+
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{}
+}
+
+func (h *HealthHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/health/dependencies", h.Dependencies)
+}
+
+// Dependencies menampilkan state circuit breaker setiap client keluar
+func (h *HealthHandler) Dependencies(c *fiber.Ctx) error {
+	snapshots := circuitbreaker.Snapshots()
+	status := "ok"
+	for _, s := range snapshots {
+		if s.State != circuitbreaker.StateClosed.String() {
+			status = "degraded"
+			break
+		}
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success get dependency health",
+		Data: fiber.Map{
+			"status":       status,
+			"dependencies": snapshots,
+		},
+	})
+}
@@ -0,0 +1,375 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/dto"
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/fadilmartias/cv-analyzer/internal/usecase"
+	"github.com/fadilmartias/cv-analyzer/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const maxPageSize = 100
+
+type JobHandler struct {
+	uc *usecase.JobUsecase
+}
+
+func NewJobHandler(uc *usecase.JobUsecase) *JobHandler {
+	return &JobHandler{uc: uc}
+}
+
+func (h *JobHandler) RegisterRoutes(app *fiber.App) {
+	app.Post("/jobs", h.Create)
+	app.Get("/jobs", h.List)
+	app.Get("/jobs/:id", h.Get)
+	app.Put("/jobs/:id", h.Update)
+	app.Delete("/jobs/:id", h.Delete)
+	app.Get("/jobs/:id/candidates", h.Candidates)
+}
+
+func (h *JobHandler) Create(c *fiber.Ctx) error {
+	req, err := h.parseRequest(c)
+	if err != nil {
+		return requestErrorResponse(c, err, "invalid request")
+	}
+
+	job, err := h.uc.Create(c.UserContext(), req.Title, req.Description, req.CaseStudyBrief)
+	if err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Message: "failed to create job",
+		}, err)
+	}
+
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Code:    fiber.StatusCreated,
+		Message: "Success create job",
+		Data:    toJobDTO(job),
+	})
+}
+
+func (h *JobHandler) List(c *fiber.Ctx) error {
+	page, pageSize := paginationParams(c)
+
+	jobs, pagination, err := h.uc.List(page, pageSize)
+	if err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Message: "failed to get jobs",
+		}, err)
+	}
+
+	data := make([]dto.JobDTO, 0, len(jobs))
+	for i := range jobs {
+		data = append(data, toJobDTO(&jobs[i]))
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message:    "Success get jobs",
+		Data:       data,
+		Pagination: pagination,
+	})
+}
+
+func (h *JobHandler) Get(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return h.jobError(c, usecase.ErrJobNotFound, "")
+	}
+
+	job, err := h.uc.Get(id)
+	if err != nil {
+		return h.jobError(c, err, "failed to get job")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success get job",
+		Data:    toJobDTO(job),
+	})
+}
+
+func (h *JobHandler) Update(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return h.jobError(c, usecase.ErrJobNotFound, "")
+	}
+
+	req, err := h.parseRequest(c)
+	if err != nil {
+		return requestErrorResponse(c, err, "invalid request")
+	}
+
+	job, err := h.uc.Update(c.UserContext(), id, req.Title, req.Description, req.CaseStudyBrief)
+	if err != nil {
+		return h.jobError(c, err, "failed to update job")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success update job",
+		Data:    toJobDTO(job),
+	})
+}
+
+func (h *JobHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return h.jobError(c, usecase.ErrJobNotFound, "")
+	}
+
+	if err := h.uc.Delete(id); err != nil {
+		return h.jobError(c, err, "failed to delete job")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success delete job",
+	})
+}
+
+// Candidates me-ranking kandidat yang sudah dievaluasi untuk job, baik yang
+// di-submit dengan job_id ini maupun lewat RAG dengan job ini di context_jobs
+// (job_id kosong di hasilnya). Filter:
+// ?min_score=, ?status=, ?from= & ?to= (RFC3339 atau 2006-01-02), ?skills=go,docker
+// dan ?vector_prefilter=true dengan ?top_k= & ?min_similarity= untuk menyaring
+// CV berdasarkan kemiripan embedding sebelum di-ranking
+func (h *JobHandler) Candidates(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return h.jobError(c, usecase.ErrJobNotFound, "")
+	}
+	page, pageSize := paginationParams(c)
+
+	filter, errs := rankFilterParams(c)
+	if len(errs) > 0 {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid candidate filter",
+			Details: errs,
+		})
+	}
+
+	ranked, pagination, err := h.uc.RankCandidates(id, filter, page, pageSize)
+	if err != nil {
+		return h.jobError(c, err, "failed to rank candidates")
+	}
+
+	data := make([]dto.RankedCandidateDTO, 0, len(ranked))
+	for i, r := range ranked {
+		item := dto.RankedCandidateDTO{
+			Rank:                 (page-1)*pageSize + i + 1,
+			Score:                r.Score,
+			Similarity:           r.Similarity,
+			EvaluationSummaryDTO: dto.NewEvaluationSummaryDTO(r.Task),
+			MissingSkills:        []string{},
+			MatchedNiceToHave:    []string{},
+		}
+		if gap := jobSkillGap(r.Task, id); gap != nil {
+			item.SkillCoverage = &gap.Coverage
+			item.MissingSkills = gap.Missing
+			item.MatchedNiceToHave = gap.MatchedNiceToHave
+		}
+		data = append(data, item)
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message:    "Success rank candidates",
+		Data:       data,
+		Pagination: pagination,
+	})
+}
+
+func (h *JobHandler) parseRequest(c *fiber.Ctx) (*dto.JobRequestDTO, error) {
+	var req dto.JobRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return nil, &requestError{
+			format: util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: "invalid request body",
+			},
+			err: err,
+		}
+	}
+
+	errs := map[string]string{}
+	if strings.TrimSpace(req.Title) == "" {
+		errs["title"] = "title is required"
+	}
+	if strings.TrimSpace(req.Description) == "" {
+		errs["description"] = "description is required"
+	}
+	if len(errs) > 0 {
+		return nil, &requestError{format: util.ErrorResponseFormat{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid job data",
+			Details: errs,
+		}}
+	}
+	return &req, nil
+}
+
+func (h *JobHandler) jobError(c *fiber.Ctx, err error, message string) error {
+	if errors.Is(err, usecase.ErrJobNotFound) {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusNotFound,
+			Message: "job not found",
+		})
+	}
+	return util.ErrorResponse(c, util.ErrorResponseFormat{
+		Message: message,
+	}, err)
+}
+
+func toJobDTO(job *model.Job) dto.JobDTO {
+	return dto.JobDTO{
+		ID:               job.ID,
+		Title:            job.Title,
+		Description:      job.Content,
+		CaseStudyBrief:   job.CaseStudyBrief,
+		RequiredSkills:   job.RequiredSkills,
+		NiceToHaveSkills: job.NiceToHaveSkills,
+		CreatedAt:        job.CreatedAt,
+		UpdatedAt:        job.UpdatedAt,
+	}
+}
+
+// parseID memvalidasi parameter :id sebagai UUID sebelum diteruskan ke database
+func parseID(c *fiber.Ctx) (string, error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// paginationParams membaca query page & page_size dengan default 1 dan 10
+func paginationParams(c *fiber.Ctx) (int, int) {
+	page := c.QueryInt("page", 1)
+	if page < 1 {
+		page = 1
+	}
+	pageSize := c.QueryInt("page_size", 10)
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize
+}
+
+// rankFilterParams membaca query filter ranking kandidat; error dikembalikan
+// per nama parameter
+func rankFilterParams(c *fiber.Ctx) (usecase.RankFilter, map[string]string) {
+	filter := usecase.RankFilter{
+		Status: strings.TrimSpace(c.Query("status")),
+	}
+	errs := map[string]string{}
+
+	if raw := c.Query("min_score"); raw != "" {
+		score, err := strconv.ParseFloat(raw, 64)
+		if err != nil || score < 0 || score > 10 {
+			errs["min_score"] = "min_score must be a number between 0 and 10"
+		} else {
+			filter.MinScore = &score
+		}
+	}
+	if raw := c.Query("from"); raw != "" {
+		from, _, err := parseDateParam(raw)
+		if err != nil {
+			errs["from"] = "from must be RFC3339 or YYYY-MM-DD"
+		} else {
+			filter.From = &from
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, dateOnly, err := parseDateParam(raw)
+		if err != nil {
+			errs["to"] = "to must be RFC3339 or YYYY-MM-DD"
+		} else {
+			if dateOnly {
+				// Tanggal saja berarti sampai akhir hari itu
+				to = to.AddDate(0, 0, 1)
+			}
+			filter.To = &to
+		}
+	}
+	for _, skill := range strings.Split(c.Query("skills"), ",") {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			filter.Skills = append(filter.Skills, skill)
+		}
+	}
+
+	filter.VectorPrefilter = c.QueryBool("vector_prefilter", false)
+	if raw := c.Query("top_k"); raw != "" {
+		topK, err := strconv.Atoi(raw)
+		if err != nil || topK < 1 {
+			errs["top_k"] = "top_k must be a positive integer"
+		} else {
+			filter.TopK = topK
+		}
+	}
+	if raw := c.Query("min_similarity"); raw != "" {
+		similarity, err := strconv.ParseFloat(raw, 64)
+		if err != nil || similarity < -1 || similarity > 1 {
+			errs["min_similarity"] = "min_similarity must be a number between -1 and 1"
+		} else {
+			filter.MinSimilarity = &similarity
+		}
+	}
+	if (filter.TopK > 0 || filter.MinSimilarity != nil) && !filter.VectorPrefilter {
+		errs["vector_prefilter"] = "top_k and min_similarity require vector_prefilter=true"
+	}
+	return filter, errs
+}
+
+// parseDateParam menerima RFC3339 atau tanggal saja (YYYY-MM-DD, UTC)
+func parseDateParam(raw string) (t time.Time, dateOnly bool, err error) {
+	if t, err = time.Parse(time.RFC3339, raw); err == nil {
+		return t, false, nil
+	}
+	t, err = time.Parse(time.DateOnly, raw)
+	return t, true, err
+}
+
+// jobSkillGap mengambil gap skill task terhadap job tertentu dari laporan
+// skill gap evaluasi
+func jobSkillGap(task model.EvaluationTask, jobID string) *model.JobSkillGap {
+	if task.SkillGap == nil {
+		return nil
+	}
+	for i := range task.SkillGap.Jobs {
+		if task.SkillGap.Jobs[i].JobID.String() == jobID {
+			return &task.SkillGap.Jobs[i]
+		}
+	}
+	return nil
+}
+
+// requestError adalah request yang ditolak oleh helper parsing/validasi. Helper
+// tidak menulis respons sendiri (util.ErrorResponse mengembalikan nil setelah
+// menulis), handler yang menulisnya lewat requestErrorResponse.
+type requestError struct {
+	format util.ErrorResponseFormat
+	err    error
+}
+
+func (e *requestError) Error() string {
+	if e.err != nil {
+		return e.format.Message + ": " + e.err.Error()
+	}
+	return e.format.Message
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// requestErrorResponse menulis respons untuk requestError; error lain dibalas
+// 500 dengan message
+func requestErrorResponse(c *fiber.Ctx, err error, message string) error {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return util.ErrorResponse(c, reqErr.format, reqErr.err)
+	}
+	return util.ErrorResponse(c, util.ErrorResponseFormat{
+		Message: message,
+	}, err)
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/fadilmartias/cv-analyzer/internal/dto"
+	"github.com/fadilmartias/cv-analyzer/internal/usecase"
+	"github.com/fadilmartias/cv-analyzer/internal/util"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RubricHandler struct {
+	uc *usecase.RubricUsecase
+}
+
+func NewRubricHandler(uc *usecase.RubricUsecase) *RubricHandler {
+	return &RubricHandler{uc: uc}
+}
+
+func (h *RubricHandler) RegisterRoutes(app *fiber.App) {
+	app.Post("/rubrics", h.Create)
+	app.Get("/rubrics", h.List)
+	app.Get("/rubrics/:id", h.Get)
+	app.Put("/rubrics/:id", h.Update)
+	app.Delete("/rubrics/:id", h.Delete)
+}
+
+func (h *RubricHandler) Create(c *fiber.Ctx) error {
+	var req dto.RubricRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid request body",
+		}, err)
+	}
+
+	rubric, err := h.uc.Create(req.ToModel())
+	if err != nil {
+		return h.rubricError(c, err, "failed to create rubric")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Code:    fiber.StatusCreated,
+		Message: "Success create rubric",
+		Data:    rubric,
+	})
+}
+
+// List mendukung filter ?job_id= dan ?active=true
+func (h *RubricHandler) List(c *fiber.Ctx) error {
+	page, pageSize := paginationParams(c)
+
+	var jobID *uuid.UUID
+	if raw := c.Query("job_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return util.ErrorResponse(c, util.ErrorResponseFormat{
+				Code:    fiber.StatusBadRequest,
+				Message: "invalid job_id",
+			}, err)
+		}
+		jobID = &id
+	}
+
+	rubrics, pagination, err := h.uc.List(jobID, c.QueryBool("active"), page, pageSize)
+	if err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Message: "failed to get rubrics",
+		}, err)
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message:    "Success get rubrics",
+		Data:       rubrics,
+		Pagination: pagination,
+	})
+}
+
+func (h *RubricHandler) Get(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return h.rubricError(c, usecase.ErrRubricNotFound, "")
+	}
+
+	rubric, err := h.uc.Get(id)
+	if err != nil {
+		return h.rubricError(c, err, "failed to get rubric")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success get rubric",
+		Data:    rubric,
+	})
+}
+
+// Update membuat versi baru dari rubric; versi lama tetap tersimpan
+func (h *RubricHandler) Update(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return h.rubricError(c, usecase.ErrRubricNotFound, "")
+	}
+
+	var req dto.RubricRequestDTO
+	if err := c.BodyParser(&req); err != nil {
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusBadRequest,
+			Message: "invalid request body",
+		}, err)
+	}
+
+	rubric, err := h.uc.Update(id, req.ToModel())
+	if err != nil {
+		return h.rubricError(c, err, "failed to update rubric")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success update rubric",
+		Data:    rubric,
+	})
+}
+
+func (h *RubricHandler) Delete(c *fiber.Ctx) error {
+	id, err := parseID(c)
+	if err != nil {
+		return h.rubricError(c, usecase.ErrRubricNotFound, "")
+	}
+
+	if err := h.uc.Deactivate(id); err != nil {
+		return h.rubricError(c, err, "failed to delete rubric")
+	}
+	return util.SuccessResponse(c, util.SuccessResponseFormat{
+		Message: "Success deactivate rubric",
+	})
+}
+
+func (h *RubricHandler) rubricError(c *fiber.Ctx, err error, message string) error {
+	var formErr *util.FormError
+	switch {
+	case errors.Is(err, usecase.ErrRubricNotFound):
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusNotFound,
+			Message: "rubric not found",
+		})
+	case errors.As(err, &formErr):
+		return util.ErrorResponse(c, util.ErrorResponseFormat{
+			Code:    fiber.StatusBadRequest,
+			Message: formErr.Message,
+			Details: formErr.Errors,
+		})
+	}
+	return util.ErrorResponse(c, util.ErrorResponseFormat{
+		Message: message,
+	}, err)
+}
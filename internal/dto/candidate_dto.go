@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CandidateDTO struct {
+	ID               uuid.UUID  `json:"id"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	Phone            string     `json:"phone"`
+	EvaluationCount  int64      `json:"evaluation_count"`
+	LastEvaluationAt *time.Time `json:"last_evaluation_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// CandidateEvaluationDTO adalah satu evaluasi dalam riwayat candidate, dengan
+// perubahan skor dibanding evaluasi selesai sebelumnya untuk job yang sama
+type CandidateEvaluationDTO struct {
+	EvaluationSummaryDTO
+	Change *ScoreChangeDTO `json:"change"` // kosong untuk evaluasi pertama per job / yang belum selesai
+}
+
+// ScoreChangeDTO adalah selisih skor terhadap evaluasi pembanding (to - from)
+type ScoreChangeDTO struct {
+	PreviousID   uuid.UUID `json:"previous_id"`
+	CvMatchRate  float64   `json:"cv_match_rate"`
+	ProjectScore float64   `json:"project_score"`
+}
+
+// EvaluationComparisonDTO membandingkan dua evaluasi seorang candidate
+type EvaluationComparisonDTO struct {
+	From          EvaluationSummaryDTO     `json:"from"`
+	To            EvaluationSummaryDTO     `json:"to"`
+	SameJob       bool                     `json:"same_job"` // skor hanya benar-benar sebanding untuk job yang sama
+	Change        ScoreChangeDTO           `json:"change"`
+	Parameters    []ParameterChangeDTO     `json:"parameters"` // perubahan skor rubric 1-5 per parameter
+	SkillsAdded   []string                 `json:"skills_added"`
+	SkillsRemoved []string                 `json:"skills_removed"`
+	Experience    *ExperienceChangeDTO     `json:"experience,omitempty"`
+	SkillCoverage []SkillCoverageChangeDTO `json:"skill_coverage"`
+}
+
+// ParameterChangeDTO adalah perubahan skor satu parameter rubric
+type ParameterChangeDTO struct {
+	Section   string   `json:"section"` // "cv" atau "project_report"
+	Parameter string   `json:"parameter"`
+	From      *float64 `json:"from"` // kosong kalau parameter tidak ada di rubric evaluasi tersebut
+	To        *float64 `json:"to"`
+	Change    *float64 `json:"change"`
+}
+
+// ExperienceChangeDTO adalah perubahan lama pengalaman hasil timeline
+type ExperienceChangeDTO struct {
+	TotalYears    float64 `json:"total_years"`
+	RelevantYears float64 `json:"relevant_years"`
+}
+
+// SkillCoverageChangeDTO adalah perubahan porsi skill wajib job yang dimiliki
+type SkillCoverageChangeDTO struct {
+	JobID    uuid.UUID `json:"job_id"`
+	JobTitle string    `json:"job_title"`
+	From     float64   `json:"from"`
+	To       float64   `json:"to"`
+	Change   float64   `json:"change"`
+	Gained   []string  `json:"gained"` // skill wajib yang sebelumnya missing
+}
@@ -0,0 +1,150 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// Nomor telepon internasional / Indonesia: +62 812-3456-7890, (021) 555 1234, 0812 3456 7890
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.\-]?)?(?:\(\d{2,4}\)[\s.\-]?)?\d{2,4}(?:[\s.\-]?\d{2,4}){2,4}`)
+	urlPattern   = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"')\]]+|\b(?:linkedin\.com|github\.com|gitlab\.com)/[^\s<>"')\]]+`)
+)
+
+// ParseCandidateProfile mem-parse output stage extract. Field yang tidak ada di
+// CV boleh kosong, tapi semua field harus ada, summary tidak boleh kosong dan
+// email (kalau diisi) harus valid.
+func ParseCandidateProfile(text string) (*model.CandidateProfile, error) {
+	raw, problems := requireFields(text, "name", "contact.email", "contact.phone", "location", "summary",
+		"education", "experiences", "skills", "certifications", "languages", "links", "projects", "achievements")
+	if raw == "" {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	var profile model.CandidateProfile
+	if err := json.Unmarshal([]byte(raw), &profile); err != nil {
+		problems = append(problems, fmt.Sprintf("cannot decode: %v", err))
+		return nil, &ValidationError{Problems: problems}
+	}
+	if strings.TrimSpace(profile.Summary) == "" {
+		problems = append(problems, "summary must not be empty")
+	}
+	if email := strings.TrimSpace(profile.Contact.Email); email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			problems = append(problems, fmt.Sprintf("contact.email %q is not a valid email address", email))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, &ValidationError{Problems: problems}
+	}
+	return &profile, nil
+}
+
+// ApplyProfileFallbacks melengkapi email, telepon dan link dari teks CV dengan
+// regex. Nilai dari LLM yang tidak tertulis di CV (halusinasi) diganti hasil regex.
+func ApplyProfileFallbacks(profile *model.CandidateProfile, cv string) {
+	normalized := strings.ToLower(cv)
+
+	email := strings.TrimSpace(profile.Contact.Email)
+	if email == "" || !strings.Contains(normalized, strings.ToLower(email)) {
+		if found := emailPattern.FindString(cv); found != "" {
+			email = found
+		}
+	}
+	profile.Contact.Email = email
+
+	phone := strings.TrimSpace(profile.Contact.Phone)
+	if phone == "" || !strings.Contains(digitsOnly(cv), digitsOnly(phone)) {
+		if found := findPhone(cv); found != "" {
+			phone = found
+		}
+	}
+	profile.Contact.Phone = phone
+
+	seen := map[string]bool{}
+	links := make([]string, 0, len(profile.Links))
+	for _, link := range append(profile.Links, urlPattern.FindAllString(cv, -1)...) {
+		link = strings.TrimRight(strings.TrimSpace(link), ".,;:")
+		key := strings.TrimPrefix(strings.TrimPrefix(strings.ToLower(link), "https://"), "http://")
+		key = strings.TrimSuffix(strings.TrimPrefix(key, "www."), "/")
+		if link == "" || seen[key] || strings.Contains(link, "@") {
+			continue
+		}
+		seen[key] = true
+		links = append(links, link)
+	}
+	profile.Links = links
+}
+
+// findPhone mengambil kandidat nomor telepon pertama dengan 9-15 digit, supaya
+// tahun ("2019 - 2021") atau tanggal tidak dianggap nomor telepon
+func findPhone(text string) string {
+	for _, match := range phonePattern.FindAllString(text, -1) {
+		if n := len(digitsOnly(match)); n >= 9 && n <= 15 {
+			return strings.TrimSpace(match)
+		}
+	}
+	return ""
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CandidateProfileSchema adalah JSON Schema untuk model.CandidateProfile
+func CandidateProfileSchema() map[string]any {
+	str := map[string]any{"type": "string"}
+	strList := arraySchema(str)
+	return objectSchema(map[string]any{
+		"name": str,
+		"contact": objectSchema(map[string]any{
+			"email": str,
+			"phone": str,
+		}),
+		"location": str,
+		"summary":  str,
+		"education": arraySchema(objectSchema(map[string]any{
+			"institution": str,
+			"degree":      str,
+			"field":       str,
+			"start_date":  str,
+			"end_date":    str,
+		})),
+		"experiences": arraySchema(objectSchema(map[string]any{
+			"title":       str,
+			"company":     str,
+			"location":    str,
+			"start_date":  str,
+			"end_date":    str,
+			"description": str,
+		})),
+		"skills": strList,
+		"certifications": arraySchema(objectSchema(map[string]any{
+			"name":   str,
+			"issuer": str,
+			"date":   str,
+		})),
+		"languages": strList,
+		"links":     strList,
+		"projects": arraySchema(objectSchema(map[string]any{
+			"name":         str,
+			"description":  str,
+			"technologies": strList,
+		})),
+		"achievements": strList,
+	})
+}
@@ -0,0 +1,208 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/tidwall/gjson"
+)
+
+// Output tiap stage pipeline evaluasi. Schema yang sama dikirim ke provider
+// (*Schema) dan divalidasi ulang di sini. LLM hanya memberi skor rubric 1-5;
+// cv_match_rate & project_score dihitung di Go.
+
+// SectionEvaluation adalah hasil stage cv_evaluation / project_evaluation:
+// skor 1-5 per parameter rubric section tersebut beserta feedback-nya
+type SectionEvaluation struct {
+	Scores   map[string]float64 `json:"scores"`
+	Feedback string             `json:"feedback"`
+}
+
+// Synthesis adalah hasil stage synthesis
+type Synthesis struct {
+	OverallSummary string `json:"overall_summary"`
+}
+
+// EvaluationBreakdown berisi skor 1-5 per parameter rubric, per section
+type EvaluationBreakdown struct {
+	CV            map[string]float64 `json:"cv"`
+	ProjectReport map[string]float64 `json:"project_report"`
+}
+
+// Section mengembalikan skor untuk section "cv" atau "project_report"
+func (b EvaluationBreakdown) Section(section string) map[string]float64 {
+	switch section {
+	case model.RubricSectionCV:
+		return b.CV
+	case model.RubricSectionProjectReport:
+		return b.ProjectReport
+	default:
+		return nil
+	}
+}
+
+// ScoreDetails menyimpan skor mentah dari LLM beserta hasil perhitungan berbobot,
+// supaya skor akhir bisa direproduksi dan diaudit
+type ScoreDetails struct {
+	CV            ScoreSection `json:"cv"`
+	ProjectReport ScoreSection `json:"project_report"`
+}
+
+type ScoreSection struct {
+	Components      []ScoreComponent `json:"components"`
+	WeightedAverage float64          `json:"weighted_average"` // skala 1-5
+	Score           float64          `json:"score"`            // cv: 0-1, project: 0-10
+}
+
+type ScoreComponent struct {
+	Parameter string  `json:"parameter"`
+	Score     float64 `json:"score"`    // skor mentah 1-5 dari LLM
+	Weight    float64 `json:"weight"`   // bobot dalam persen
+	Weighted  float64 `json:"weighted"` // score * weight / 100
+}
+
+// ValidationError berisi semua pelanggaran schema pada output LLM
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid LLM output: " + strings.Join(e.Problems, "; ")
+}
+
+// ParseSectionEvaluation mem-parse output stage cv_evaluation / project_evaluation
+// secara strict terhadap parameter rubric: semua parameter wajib ada, tidak ada
+// parameter asing, skor 1-5, feedback tidak kosong
+func ParseSectionEvaluation(text string, params []model.RubricParameter) (*SectionEvaluation, error) {
+	raw, problems := requireFields(text, "scores", "feedback")
+	if raw == "" {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	var result SectionEvaluation
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		problems = append(problems, fmt.Sprintf("cannot decode: %v", err))
+		return nil, &ValidationError{Problems: problems}
+	}
+	if strings.TrimSpace(result.Feedback) == "" {
+		problems = append(problems, "feedback must not be empty")
+	}
+
+	known := map[string]bool{}
+	for _, p := range params {
+		known[p.Key] = true
+		path := "scores." + p.Key
+		v, ok := result.Scores[p.Key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("missing field %s", path))
+			continue
+		}
+		if v < 1 || v > 5 {
+			problems = append(problems, fmt.Sprintf("%s must be between 1 and 5, got %v", path, v))
+		}
+	}
+	for key := range result.Scores {
+		if !known[key] {
+			problems = append(problems, fmt.Sprintf("unknown field scores.%s", key))
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, &ValidationError{Problems: problems}
+	}
+	return &result, nil
+}
+
+// ParseSynthesis mem-parse output stage synthesis
+func ParseSynthesis(text string) (*Synthesis, error) {
+	raw, problems := requireFields(text, "overall_summary")
+	if raw == "" {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	var result Synthesis
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		problems = append(problems, fmt.Sprintf("cannot decode: %v", err))
+		return nil, &ValidationError{Problems: problems}
+	}
+	if strings.TrimSpace(result.OverallSummary) == "" {
+		problems = append(problems, "overall_summary must not be empty")
+	}
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return &result, nil
+}
+
+// requireFields mengambil objek JSON dari output LLM dan mencatat field wajib
+// yang tidak ada. raw kosong berarti output bukan JSON yang valid.
+func requireFields(text string, fields ...string) (string, []string) {
+	raw := extractJSONObject(text)
+	if !gjson.Valid(raw) {
+		return "", []string{"output is not valid JSON"}
+	}
+	var problems []string
+	for _, path := range fields {
+		if !gjson.Get(raw, path).Exists() {
+			problems = append(problems, fmt.Sprintf("missing field %s", path))
+		}
+	}
+	return raw, problems
+}
+
+// SectionEvaluationSchema adalah JSON Schema untuk SectionEvaluation sesuai
+// parameter rubric satu section
+func SectionEvaluationSchema(params []model.RubricParameter) map[string]any {
+	scores := map[string]any{}
+	for _, p := range params {
+		scores[p.Key] = numberSchema(1, 5)
+	}
+	return objectSchema(map[string]any{
+		"scores":   objectSchema(scores),
+		"feedback": map[string]any{"type": "string"},
+	})
+}
+
+// SynthesisSchema adalah JSON Schema untuk Synthesis
+func SynthesisSchema() map[string]any {
+	return objectSchema(map[string]any{
+		"overall_summary": map[string]any{"type": "string"},
+	})
+}
+
+func objectSchema(properties map[string]any) map[string]any {
+	required := make([]string, 0, len(properties))
+	for key := range properties {
+		required = append(required, key)
+	}
+	sort.Strings(required)
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+func arraySchema(items map[string]any) map[string]any {
+	return map[string]any{"type": "array", "items": items}
+}
+
+func numberSchema(min, max float64) map[string]any {
+	return map[string]any{"type": "number", "minimum": min, "maximum": max}
+}
+
+// extractJSONObject membuang ```json fence atau teks di luar objek JSON terluar
+func extractJSONObject(text string) string {
+	text = strings.TrimSpace(text)
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end <= start {
+		return text
+	}
+	return text[start : end+1]
+}
@@ -1,20 +1,121 @@
 package dto
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/fadilmartias/cv-analyzer/internal/model"
 	"github.com/google/uuid"
 )
 
 type EvaluationTaskDTO struct {
-	ID              uuid.UUID `json:"id"`
-	Status          string    `json:"status"` // e.g. "processing", "completed", "failed"
-	CvMatchRate     float64   `json:"cv_match_rate"`
-	CvFeedback      string    `json:"cv_feedback"`
-	ProjectScore    float64   `json:"project_score"`
-	ProjectFeedback string    `json:"project_feedback"`
-	OverallSummary  string    `json:"overall_summary"`
-	Breakdown       string    `json:"breakdown"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID                uuid.UUID               `json:"id"`
+	JobID             *uuid.UUID              `json:"job_id"`
+	CandidateID       *uuid.UUID              `json:"candidate_id"`
+	RubricID          *uuid.UUID              `json:"rubric_id"`
+	Status            string                  `json:"status"` // e.g. "extracting", "evaluating", "completed", "failed"
+	StatusHistory     []model.StatusChange    `json:"status_history"`
+	Language          string                  `json:"language"` // bahasa CV hasil deteksi
+	CvMatchRate       float64                 `json:"cv_match_rate"`
+	CvFeedback        string                  `json:"cv_feedback"`
+	ProjectScore      float64                 `json:"project_score"`
+	ProjectFeedback   string                  `json:"project_feedback"`
+	OverallSummary    string                  `json:"overall_summary"`
+	Breakdown         string                  `json:"breakdown"`
+	SkillGap          *model.SkillGapReport   `json:"skill_gap"`
+	ScoreDetails      *ScoreDetails           `json:"score_details,omitempty"`
+	ContextJobs       []model.ContextJob      `json:"context_jobs"`
+	Profile           *model.CandidateProfile `json:"profile"`
+	Timeline          *model.Timeline         `json:"timeline"`
+	CVDocument        *DocumentDTO            `json:"cv_document"`
+	ReportDocument    *DocumentDTO            `json:"report_document"`
+	Extraction        json.RawMessage         `json:"extraction"`
+	ExtractionQuality float64                 `json:"extraction_quality"`
+	LowQuality        bool                    `json:"low_quality"`
+	Stages            []EvaluationStageDTO    `json:"stages"`
+	ReusedFromID      *uuid.UUID              `json:"reused_from_id,omitempty"` // task asal kalau hasil evaluasi disalin
+	Provider          string                  `json:"provider"`
+	Model             string                  `json:"model"`
+	Error             string                  `json:"error,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+	UpdatedAt         time.Time               `json:"updated_at"`
+}
+
+// EvaluationStageDTO adalah ringkasan satu stage pipeline (tanpa input/output lengkap)
+type EvaluationStageDTO struct {
+	Stage     string    `json:"stage"`
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	Provider  string    `json:"provider"`
+	Model     string    `json:"model"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DocumentDTO adalah metadata file yang di-upload (tanpa storage key)
+type DocumentDTO struct {
+	ID        uuid.UUID `json:"id"`
+	Filename  string    `json:"filename"`
+	Size      int64     `json:"size"`
+	MIMEType  string    `json:"mime_type"`
+	Hash      string    `json:"sha256"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewDocumentDTO(doc model.Document) *DocumentDTO {
+	return &DocumentDTO{
+		ID:        doc.ID,
+		Filename:  doc.Filename,
+		Size:      doc.Size,
+		MIMEType:  doc.MIMEType,
+		Hash:      doc.Hash,
+		CreatedAt: doc.CreatedAt,
+	}
+}
+
+// EvaluationSummaryDTO adalah satu baris di daftar hasil evaluasi
+type EvaluationSummaryDTO struct {
+	ID            uuid.UUID  `json:"id"`
+	JobID         *uuid.UUID `json:"job_id"`
+	CandidateID   *uuid.UUID `json:"candidate_id"`
+	Status        string     `json:"status"`
+	CandidateName string     `json:"candidate_name"`
+	Email         string     `json:"email"`
+	Location      string     `json:"location"`
+	Skills        []string   `json:"skills"`
+	TotalYears    float64    `json:"total_years"`
+	RelevantYears float64    `json:"relevant_years"`
+	CvMatchRate   float64    `json:"cv_match_rate"`
+	ProjectScore  float64    `json:"project_score"`
+	LowQuality    bool       `json:"low_quality"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func NewEvaluationSummaryDTO(task model.EvaluationTask) EvaluationSummaryDTO {
+	summary := EvaluationSummaryDTO{
+		ID:           task.ID,
+		JobID:        task.JobID,
+		CandidateID:  task.CandidateID,
+		Status:       task.Status,
+		Skills:       []string{},
+		CvMatchRate:  task.CvMatchRate,
+		ProjectScore: task.ProjectScore,
+		LowQuality:   task.LowQuality,
+		CreatedAt:    task.CreatedAt,
+		UpdatedAt:    task.UpdatedAt,
+	}
+	if p := task.Profile; p != nil {
+		summary.CandidateName = p.Name
+		summary.Email = p.Contact.Email
+		summary.Location = p.Location
+		if p.Skills != nil {
+			summary.Skills = p.Skills
+		}
+	}
+	if t := task.Timeline; t != nil {
+		summary.TotalYears = t.TotalYears
+		summary.RelevantYears = t.RelevantYears
+	}
+	return summary
 }
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobRequestDTO struct {
+	Title          string `json:"title"`
+	Description    string `json:"description"`
+	CaseStudyBrief string `json:"case_study_brief"` // opsional
+}
+
+type JobDTO struct {
+	ID               uuid.UUID `json:"id"`
+	Title            string    `json:"title"`
+	Description      string    `json:"description"`
+	CaseStudyBrief   string    `json:"case_study_brief"`
+	RequiredSkills   []string  `json:"required_skills"`
+	NiceToHaveSkills []string  `json:"nice_to_have_skills"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// RankedCandidateDTO adalah satu kandidat di ranking GET /jobs/:id/candidates
+type RankedCandidateDTO struct {
+	Rank       int      `json:"rank"`
+	Score      float64  `json:"score"`      // (cv_match_rate * 10 + project_score) / 2, skala 0-10
+	Similarity *float64 `json:"similarity"` // cosine similarity embedding CV & job, kosong kalau belum ada embedding
+	EvaluationSummaryDTO
+	SkillCoverage     *float64 `json:"skill_coverage"` // porsi skill wajib job yang dimiliki
+	MissingSkills     []string `json:"missing_skills"`
+	MatchedNiceToHave []string `json:"matched_nice_to_have"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/google/uuid"
+)
+
+type RubricRequestDTO struct {
+	Name          string                  `json:"name"`
+	JobID         *uuid.UUID              `json:"job_id"`
+	CV            []model.RubricParameter `json:"cv"`
+	ProjectReport []model.RubricParameter `json:"project_report"`
+}
+
+func (r RubricRequestDTO) ToModel() model.Rubric {
+	return model.Rubric{
+		Name:              r.Name,
+		JobID:             r.JobID,
+		CVParameters:      r.CV,
+		ProjectParameters: r.ProjectReport,
+	}
+}
@@ -0,0 +1,268 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+)
+
+// MIME type yang didukung
+const (
+	MIMEPDF      = "application/pdf"
+	MIMEDOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	MIMEODT      = "application/vnd.oasis.opendocument.text"
+	MIMEText     = "text/plain"
+	MIMEMarkdown = "text/markdown"
+	MIMERTF      = "application/rtf"
+	MIMEPNG      = "image/png"
+	MIMEJPEG     = "image/jpeg"
+)
+
+// Metode ekstraksi per halaman
+const (
+	MethodText = "text" // text layer PDF atau teks dari format dokumen
+	MethodOCR  = "ocr"  // render halaman / gambar lalu Tesseract
+)
+
+// Version dinaikkan setiap kali perubahan extractor bisa mengubah hasil
+// ekstraksi, supaya hasil yang sudah di-cache tidak dipakai lagi
+const Version = 1
+
+// minContentChars adalah panjang teks minimal supaya dokumen layak dievaluasi
+const minContentChars = 100
+
+// ErrUnsupportedType dikembalikan kalau tidak ada extractor untuk MIME type file
+var ErrUnsupportedType = errors.New("unsupported file type")
+
+// Options mengatur ekstraksi satu dokumen
+type Options struct {
+	// Languages adalah bahasa OCR tesseract, mis. "ind" atau "eng+ind".
+	// Kosong = OCR_LANGUAGES, dengan deteksi bahasa otomatis kalau aktif.
+	Languages string
+}
+
+// CacheKey mengidentifikasi versi extractor dan semua setting yang mempengaruhi
+// hasil ekstraksi. File yang sama dengan CacheKey yang sama menghasilkan teks yang sama.
+func CacheKey(opts Options) string {
+	cfg := config.LoadOCRConfig()
+	languages := opts.Languages
+	if languages == "" {
+		languages = cfg.Languages
+		if cfg.AutoDetectLanguage {
+			languages += ",auto"
+		}
+	}
+	return fmt.Sprintf("v%d;lang=%s;dpi=%g;deskew=%t;binarize=%t", Version, languages, cfg.DPI, cfg.Deskew, cfg.Binarize)
+}
+
+// Document adalah hasil ekstraksi teks yang sudah dinormalisasi, sama untuk semua format
+type Document struct {
+	MIMEType     string  `json:"mime_type"`
+	Language     string  `json:"language,omitempty"`      // hasil DetectLanguage (ISO 639-1)
+	OCRLanguages string  `json:"ocr_languages,omitempty"` // bahasa tesseract yang dipakai
+	Quality      float64 `json:"quality"`                 // 0-1, rata-rata confidence berbobot jumlah karakter
+	Text         string  `json:"-"`
+	Pages        []Page  `json:"pages"`
+}
+
+type Page struct {
+	Number     int     `json:"page"`
+	Method     string  `json:"method"`     // "text" atau "ocr"
+	Confidence float64 `json:"confidence"` // 0-100; text layer dianggap 100
+	Chars      int     `json:"chars"`
+	Error      string  `json:"error,omitempty"`
+	Text       string  `json:"-"`
+}
+
+// Extractor mengekstrak teks dari file dengan satu MIME type tertentu
+type Extractor interface {
+	Extract(ctx context.Context, path string, opts Options) (*Document, error)
+}
+
+// ExtractorFunc mengubah fungsi biasa menjadi Extractor
+type ExtractorFunc func(ctx context.Context, path string, opts Options) (*Document, error)
+
+func (f ExtractorFunc) Extract(ctx context.Context, path string, opts Options) (*Document, error) {
+	return f(ctx, path, opts)
+}
+
+// Registry memetakan MIME type hasil deteksi isi file ke Extractor
+type Registry struct {
+	extractors map[string]Extractor
+}
+
+func NewRegistry() *Registry {
+	return &Registry{extractors: map[string]Extractor{}}
+}
+
+func (r *Registry) Register(mimeType string, extractor Extractor) {
+	r.extractors[mimeType] = extractor
+}
+
+// Default berisi extractor untuk semua format yang didukung
+var Default = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(MIMEPDF, ExtractorFunc(ExtractPDF))
+	r.Register(MIMEDOCX, ExtractorFunc(ExtractDOCX))
+	r.Register(MIMEODT, ExtractorFunc(ExtractODT))
+	r.Register(MIMEText, ExtractorFunc(ExtractText))
+	r.Register(MIMEMarkdown, ExtractorFunc(ExtractText))
+	r.Register(MIMERTF, ExtractorFunc(ExtractRTF))
+	r.Register(MIMEPNG, ExtractorFunc(ExtractImage))
+	r.Register(MIMEJPEG, ExtractorFunc(ExtractImage))
+	return r
+}
+
+// Supported mengecek apakah ada extractor untuk MIME type tersebut
+func Supported(mimeType string) bool {
+	return Default.Supports(mimeType)
+}
+
+func (r *Registry) Supports(mimeType string) bool {
+	_, ok := r.extractors[mimeType]
+	return ok
+}
+
+// Extract mendeteksi MIME type file dari isinya lalu menjalankan extractor yang cocok
+func Extract(ctx context.Context, path, filename string, opts Options) (*Document, error) {
+	return Default.Extract(ctx, path, filename, opts)
+}
+
+func (r *Registry) Extract(ctx context.Context, path, filename string, opts Options) (*Document, error) {
+	mimeType, err := Detect(path, filename)
+	if err != nil {
+		return nil, err
+	}
+	extractor, ok := r.extractors[mimeType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, mimeType)
+	}
+
+	doc, err := extractor.Extract(ctx, path, opts)
+	if err != nil {
+		return nil, err
+	}
+	doc.MIMEType = mimeType
+	for i := range doc.Pages {
+		doc.Pages[i].Text = normalize(doc.Pages[i].Text)
+		doc.Pages[i].Chars = len([]rune(doc.Pages[i].Text))
+	}
+	doc.Text = normalize(doc.Text)
+	doc.Language = DetectLanguage(doc.Text)
+	doc.Quality = documentQuality(doc.Pages)
+
+	if len(doc.Text) == 0 {
+		return nil, fmt.Errorf("no text extracted from document")
+	} else if len(doc.Text) < minContentChars {
+		return nil, fmt.Errorf("content too short for meaningful evaluation")
+	}
+	return doc, nil
+}
+
+// documentQuality menghitung skor kualitas 0-1 dari confidence per halaman,
+// berbobot jumlah karakter. Halaman yang gagal diekstrak dihitung 0 dengan
+// bobot rata-rata halaman lain; halaman kosong diabaikan.
+func documentQuality(pages []Page) float64 {
+	var weighted, totalChars float64
+	var counted, failed int
+	for _, page := range pages {
+		switch {
+		case page.Error != "":
+			failed++
+		case page.Chars > 0:
+			weighted += page.Confidence / 100 * float64(page.Chars)
+			totalChars += float64(page.Chars)
+			counted++
+		}
+	}
+	if counted == 0 {
+		return 0
+	}
+	totalChars += float64(failed) * totalChars / float64(counted)
+	return math.Round(weighted/totalChars*100) / 100
+}
+
+// Detect menentukan MIME type dari isi file (magic bytes). Nama file hanya dipakai
+// untuk membedakan Markdown dari teks biasa.
+func Detect(path, filename string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	head = head[:n]
+
+	if bytes.HasPrefix(head, []byte(`{\rtf`)) {
+		return MIMERTF, nil
+	}
+
+	mimeType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	switch mimeType {
+	case "application/zip":
+		return detectZip(path)
+	case MIMEText:
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".md", ".markdown":
+			return MIMEMarkdown, nil
+		}
+	}
+	return mimeType, nil
+}
+
+// detectZip membedakan DOCX dan ODT yang sama-sama berupa arsip zip
+func detectZip(path string) (string, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid zip archive: %w", err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		switch f.Name {
+		case "word/document.xml":
+			return MIMEDOCX, nil
+		case "mimetype":
+			data, err := readZipEntry(f)
+			if err == nil && strings.TrimSpace(string(data)) == MIMEODT {
+				return MIMEODT, nil
+			}
+		}
+	}
+	return "application/zip", nil
+}
+
+var (
+	trailingSpace = regexp.MustCompile(`[ \t]+\n`)
+	blankLines    = regexp.MustCompile(`\n{3,}`)
+)
+
+// normalize menyeragamkan teks hasil ekstraksi: line ending \n, tanpa spasi di
+// akhir baris dan maksimal satu baris kosong berturut-turut
+func normalize(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.ReplaceAll(text, "\u00a0", " ")
+	text = trailingSpace.ReplaceAllString(text, "\n")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,55 @@
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg" // decoder JPEG untuk image.Decode
+	_ "image/png"
+	"os"
+)
+
+// ExtractImage menjalankan OCR langsung pada foto/scan CV (PNG atau JPEG)
+func ExtractImage(ctx context.Context, path string, opts Options) (*Document, error) {
+	if err := checkTesseract(); err != nil {
+		return nil, fmt.Errorf("tesseract check failed: %w", err)
+	}
+
+	if err := validateImage(path); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	// First pass dengan semua bahasa dipakai sebagai sampel deteksi bahasa
+	var sample *ocrResult
+	var sampleErr error
+	sampleLanguages := ""
+	languages := resolveOCRLanguages(opts, func(all string) string {
+		sampleLanguages = all
+		sample, sampleErr = ocrImage(ctx, img, all)
+		if sampleErr != nil {
+			return ""
+		}
+		return sample.Text
+	})
+
+	result, err := sample, sampleErr
+	if languages != sampleLanguages {
+		result, err = ocrImage(ctx, img, languages)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := singlePage(result.Text, MethodOCR, result.Confidence)
+	doc.OCRLanguages = languages
+	return doc, nil
+}
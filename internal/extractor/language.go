@@ -0,0 +1,86 @@
+package extractor
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Kode bahasa ISO 639-1 hasil deteksi
+const (
+	LanguageEnglish    = "en"
+	LanguageIndonesian = "id"
+)
+
+// minLanguageWords adalah jumlah kata minimal supaya bahasa bisa dideteksi
+const minLanguageWords = 20
+
+// languageStopwords berisi kata fungsi yang sering muncul di CV/laporan per bahasa
+var languageStopwords = map[string][]string{
+	LanguageEnglish: {
+		"the", "and", "of", "to", "in", "for", "with", "on", "is", "are", "was", "were",
+		"as", "at", "by", "an", "be", "this", "that", "from", "or", "have", "has", "my",
+		"i", "it", "which", "will", "can", "using", "into", "their", "our", "we",
+	},
+	LanguageIndonesian: {
+		"dan", "yang", "di", "ke", "dari", "untuk", "dengan", "pada", "dalam", "ini",
+		"itu", "adalah", "sebagai", "saya", "oleh", "atau", "juga", "tidak", "akan",
+		"telah", "sudah", "serta", "bagi", "kami", "secara", "melalui", "memiliki",
+		"mampu", "menggunakan", "tersebut", "dapat", "agar", "bidang", "selama",
+	},
+}
+
+// tesseractLanguages memetakan kode ISO ke kode bahasa tesseract
+var tesseractLanguages = map[string]string{
+	LanguageEnglish:    "eng",
+	LanguageIndonesian: "ind",
+}
+
+var stopwordIndex = buildStopwordIndex()
+
+func buildStopwordIndex() map[string][]string {
+	index := map[string][]string{}
+	for lang, words := range languageStopwords {
+		for _, w := range words {
+			index[w] = append(index[w], lang)
+		}
+	}
+	return index
+}
+
+// DetectLanguage menebak bahasa teks dari frekuensi stopword. Mengembalikan ""
+// kalau teks terlalu pendek atau tidak ada bahasa yang dominan.
+func DetectLanguage(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) < minLanguageWords {
+		return ""
+	}
+
+	scores := map[string]int{}
+	for _, w := range words {
+		for _, lang := range stopwordIndex[w] {
+			scores[lang]++
+		}
+	}
+
+	best, bestScore, secondScore := "", 0, 0
+	for lang, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, secondScore = lang, score, bestScore
+		case score > secondScore:
+			secondScore = score
+		}
+	}
+	// Minimal 3% kata adalah stopword dan unggul jelas dari bahasa lain
+	if bestScore*100 < len(words)*3 || bestScore <= secondScore*5/4 {
+		return ""
+	}
+	return best
+}
+
+// TesseractLanguage mengembalikan kode tesseract untuk kode ISO, "" kalau tidak dikenal
+func TesseractLanguage(lang string) string {
+	return tesseractLanguages[lang]
+}
@@ -0,0 +1,196 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+)
+
+// processPool membatasi jumlah proses tesseract yang jalan bersamaan di seluruh
+// server, supaya beberapa upload sekaligus tidak melebihi OCR_CPU_BUDGET
+type processPool struct {
+	slots   chan struct{}
+	threads int
+}
+
+var (
+	ocrPool     *processPool
+	ocrPoolOnce sync.Once
+)
+
+func tesseractPool() *processPool {
+	ocrPoolOnce.Do(func() {
+		cfg := config.LoadOCRConfig()
+		ocrPool = &processPool{
+			slots:   make(chan struct{}, cfg.MaxProcesses()),
+			threads: cfg.ThreadsPerProcess,
+		}
+	})
+	return ocrPool
+}
+
+// ocrResult adalah teks hasil OCR beserta rata-rata confidence per kata (0-100)
+type ocrResult struct {
+	Text       string
+	Confidence float64
+	Words      int
+}
+
+// runTesseract menjalankan OCR pada file gambar dengan output TSV supaya
+// confidence per kata ikut terbaca. Proses tesseract di-kill kalau ctx
+// dibatalkan (timeout atau request dibatalkan).
+func runTesseract(ctx context.Context, path, languages string) (*ocrResult, error) {
+	pool := tesseractPool()
+	select {
+	case pool.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-pool.slots }()
+
+	cmd := exec.CommandContext(ctx, "tesseract", path, "stdout", "-l", languages, "tsv")
+	cmd.Env = append(os.Environ(), "OMP_THREAD_LIMIT="+strconv.Itoa(pool.threads))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tesseract error: %w, output: %s", err, stderr.String())
+	}
+	return parseTesseractTSV(string(out)), nil
+}
+
+// parseTesseractTSV menyusun ulang teks dari baris kata (level 5) output TSV
+// tesseract dan menghitung confidence rata-rata berbobot panjang kata
+func parseTesseractTSV(tsv string) *ocrResult {
+	const (
+		colLevel = 0
+		colBlock = 2
+		colPar   = 3
+		colLine  = 4
+		colConf  = 10
+		colText  = 11
+	)
+
+	var b strings.Builder
+	var weighted, totalChars float64
+	words := 0
+	prevBlock, prevPar, prevLine := "", "", ""
+	for i, row := range strings.Split(tsv, "\n") {
+		cols := strings.Split(strings.TrimRight(row, "\r"), "\t")
+		if i == 0 || len(cols) <= colText || cols[colLevel] != "5" {
+			continue
+		}
+		text := strings.TrimSpace(cols[colText])
+		conf, err := strconv.ParseFloat(cols[colConf], 64)
+		if text == "" || err != nil || conf < 0 {
+			continue
+		}
+
+		switch {
+		case words == 0:
+		case cols[colBlock] != prevBlock || cols[colPar] != prevPar:
+			b.WriteString("\n\n")
+		case cols[colLine] != prevLine:
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(' ')
+		}
+		b.WriteString(text)
+		prevBlock, prevPar, prevLine = cols[colBlock], cols[colPar], cols[colLine]
+
+		chars := float64(len([]rune(text)))
+		weighted += conf * chars
+		totalChars += chars
+		words++
+	}
+
+	result := &ocrResult{Text: b.String(), Words: words}
+	if totalChars > 0 {
+		result.Confidence = weighted / totalChars
+	}
+	return result
+}
+
+// ocrImage menjalankan preprocessing sesuai config lalu OCR pada gambar
+func ocrImage(ctx context.Context, img image.Image, languages string) (*ocrResult, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	cfg := config.LoadOCRConfig()
+	img = preprocess(img, preprocessOptions{Deskew: cfg.Deskew, Binarize: cfg.Binarize})
+
+	// Buat temporary file di sistem temp folder
+	tmpFile, err := os.CreateTemp("", "page-*.png")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+	defer os.Remove(tmpPath)
+
+	if err := savePNG(tmpPath, img); err != nil {
+		return nil, fmt.Errorf("failed to save PNG: %w", err)
+	}
+	return runTesseract(ctx, tmpPath, languages)
+}
+
+func savePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+
+	if err := png.Encode(f, img); err != nil {
+		return fmt.Errorf("failed to encode PNG: %w", err)
+	}
+
+	return nil
+}
+
+// resolveOCRLanguages menentukan bahasa OCR: bahasa dari request, bahasa hasil
+// deteksi sampel kalau OCR_AUTO_DETECT_LANGUAGE aktif, atau OCR_LANGUAGES.
+// sample dipanggil dengan semua bahasa terpasang untuk mendapatkan teks first pass.
+func resolveOCRLanguages(opts Options, sample func(languages string) string) string {
+	if opts.Languages != "" {
+		return opts.Languages
+	}
+	cfg := config.LoadOCRConfig()
+	if !cfg.AutoDetectLanguage {
+		return cfg.Languages
+	}
+
+	code := TesseractLanguage(DetectLanguage(sample(cfg.Languages)))
+	for _, installed := range cfg.LanguageList() {
+		if code != "" && installed == code {
+			log.Printf("Detected OCR language: %s", code)
+			return code
+		}
+	}
+	return cfg.Languages
+}
+
+// checkTesseract memverifikasi apakah tesseract terinstall dan bisa dijalankan
+func checkTesseract() error {
+	cmd := exec.Command("tesseract", "-v")
+	out, err := cmd.CombinedOutput()
+	log.Println("Tesseract Output:", string(out))
+	if err != nil {
+		return fmt.Errorf("tesseract not found or not executable: %w\nOutput: %s", err, string(out))
+	}
+	log.Printf("Tesseract version: %s\n", strings.Split(string(out), "\n")[0])
+	return nil
+}
@@ -0,0 +1,154 @@
+package extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+)
+
+// ExtractDOCX mengambil teks dari word/document.xml di dalam arsip DOCX
+func ExtractDOCX(_ context.Context, path string, _ Options) (*Document, error) {
+	data, err := readZipFile(path, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	inText, inTabs := false, false
+	err = walkXML(data, func(tok xml.Token) {
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tabs":
+				// definisi tab stop di paragraph properties, bukan karakter tab
+				inTabs = true
+			case "tab":
+				if !inTabs {
+					b.WriteByte('\t')
+				}
+			case "br", "cr":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "tabs":
+				inTabs = false
+			case "p":
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DOCX: %w", err)
+	}
+	return singlePage(b.String(), MethodText, 100), nil
+}
+
+// ExtractODT mengambil teks paragraf & heading dari content.xml di dalam arsip ODT
+func ExtractODT(_ context.Context, path string, _ Options) (*Document, error) {
+	data, err := readZipFile(path, "content.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	depth := 0 // kedalaman text:p / text:h yang sedang dibaca
+	err = walkXML(data, func(tok xml.Token) {
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p", "h":
+				depth++
+			case "s":
+				count := 1
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "c" {
+						if c, err := strconv.Atoi(attr.Value); err == nil && c > 0 {
+							count = c
+						}
+					}
+				}
+				b.WriteString(strings.Repeat(" ", count))
+			case "tab":
+				b.WriteByte('\t')
+			case "line-break":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			if (t.Name.Local == "p" || t.Name.Local == "h") && depth > 0 {
+				depth--
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			if depth > 0 {
+				b.Write(t)
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ODT: %w", err)
+	}
+	return singlePage(b.String(), MethodText, 100), nil
+}
+
+func walkXML(data []byte, visit func(xml.Token)) error {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		visit(tok)
+	}
+}
+
+func readZipFile(path, name string) ([]byte, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.Name == name {
+			return readZipEntry(f)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
+
+func readZipEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return limitedReadAll(rc, config.LoadUploadConfig().MaxUncompressedSize)
+}
+
+// singlePage membungkus teks dokumen tanpa konsep halaman menjadi satu halaman
+func singlePage(text, method string, confidence float64) *Document {
+	return &Document{
+		Text:  text,
+		Pages: []Page{{Number: 1, Method: method, Confidence: confidence, Text: text}},
+	}
+}
@@ -0,0 +1,215 @@
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+	"github.com/gen2brain/go-fitz"
+)
+
+// minTextLayerChars adalah jumlah karakter minimal supaya text layer halaman dianggap ada
+const minTextLayerChars = 20
+
+// ExtractPDF ekstrak teks dari PDF. Text layer dipakai kalau ada dan terbaca;
+// hanya halaman tanpa text layer (hasil scan) atau yang isinya rusak yang di-OCR,
+// secara paralel sebanyak OCR_WORKERS halaman.
+func ExtractPDF(ctx context.Context, path string, opts Options) (*Document, error) {
+	doc, err := openPDF(path)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+
+	log.Printf("Total pages: %d\n", doc.NumPage())
+	if err := checkPageCount(doc.NumPage()); err != nil {
+		return nil, err
+	}
+
+	pages := make([]Page, doc.NumPage())
+	var ocrPages []int
+	for n := range pages {
+		pages[n] = Page{Number: n + 1, Method: MethodText}
+
+		text, err := doc.Text(n)
+		if err != nil {
+			log.Printf("Page %d: failed to read text layer: %v", n+1, err)
+		}
+		text = strings.TrimSpace(text)
+		if usableTextLayer(text) {
+			pages[n].Text = text
+			pages[n].Confidence = 100
+		} else {
+			pages[n].Method = MethodOCR
+			ocrPages = append(ocrPages, n)
+		}
+	}
+
+	var result Document
+	var lastErr error
+	if len(ocrPages) > 0 {
+		// Cek tesseract hanya kalau memang ada halaman yang perlu di-OCR
+		log.Println("Checking tesseract...")
+		if err := checkTesseract(); err != nil {
+			return nil, fmt.Errorf("tesseract check failed: %w", err)
+		}
+
+		result.OCRLanguages = resolveOCRLanguages(opts, func(all string) string {
+			if sample := textLayerSample(pages); DetectLanguage(sample) != "" {
+				return sample
+			}
+			// First pass: halaman pertama di-OCR dengan semua bahasa sebagai sampel;
+			// hasilnya dipakai, jadi halaman ini tidak di-OCR ulang
+			n := ocrPages[0]
+			result, err := ocrPage(ctx, doc, n, all)
+			if err != nil {
+				return ""
+			}
+			pages[n].Text = result.Text
+			pages[n].Confidence = result.Confidence
+			ocrPages = ocrPages[1:]
+			return result.Text
+		})
+
+		lastErr = ocrPDFPages(ctx, path, pages, ocrPages, result.OCRLanguages)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("OCR cancelled: %w", ctx.Err())
+		}
+	}
+
+	var fullText bytes.Buffer
+	for i := range pages {
+		page := &pages[i]
+		page.Chars = len([]rune(page.Text))
+		log.Printf("Page %d extracted via %s: %d chars, confidence %.1f\n", page.Number, page.Method, page.Chars, page.Confidence)
+		if len(page.Text) > 0 {
+			fullText.WriteString(page.Text)
+			fullText.WriteString("\n\n")
+		}
+	}
+	result.Pages = pages
+	result.Text = strings.TrimSpace(fullText.String())
+
+	if len(result.Text) == 0 {
+		if lastErr != nil {
+			return nil, fmt.Errorf("failed to extract text via OCR: %w", lastErr)
+		}
+		return nil, fmt.Errorf("no text extracted from PDF (PDF might be empty or images are unreadable)")
+	}
+
+	log.Printf("Total extracted text: %d chars\n", len(result.Text))
+	return &result, nil
+}
+
+// ocrPDFPages me-render dan meng-OCR halaman secara paralel. Tiap worker membuka
+// dokumen sendiri karena satu fitz.Document tidak bisa me-render bersamaan.
+// Hasil ditulis langsung ke pages[n]; error terakhir dikembalikan.
+func ocrPDFPages(ctx context.Context, path string, pages []Page, indexes []int, languages string) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+	workers := min(config.LoadOCRConfig().Workers, len(indexes))
+	queue := make(chan int)
+	errs := make(chan error, len(indexes))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doc, err := fitz.New(path)
+			if err != nil {
+				for n := range queue {
+					pages[n].Error = err.Error()
+					errs <- fmt.Errorf("page %d: %w", n+1, err)
+				}
+				return
+			}
+			defer doc.Close()
+
+			for n := range queue {
+				result, err := ocrPage(ctx, doc, n, languages)
+				if err != nil {
+					pages[n].Error = err.Error()
+					errs <- fmt.Errorf("page %d: %w", n+1, err)
+					log.Printf("Page %d: %v", n+1, err)
+					continue
+				}
+				pages[n].Text = result.Text
+				pages[n].Confidence = result.Confidence
+			}
+		}()
+	}
+
+	for _, n := range indexes {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- n
+	}
+	close(queue)
+	wg.Wait()
+	close(errs)
+
+	var lastErr error
+	for err := range errs {
+		lastErr = err
+	}
+	return lastErr
+}
+
+// ocrPage me-render satu halaman dengan OCR_DPI lalu menjalankan OCR
+func ocrPage(ctx context.Context, doc *fitz.Document, n int, languages string) (*ocrResult, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	// Ukuran dicek sebelum render: render halaman raksasa bisa menghabiskan memori
+	if err := checkPageSize(doc, n); err != nil {
+		return nil, err
+	}
+	img, err := doc.ImageDPI(n, config.LoadOCRConfig().DPI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract image: %w", err)
+	}
+	return ocrImage(ctx, img, languages)
+}
+
+// textLayerSample menggabungkan teks dari halaman yang punya text layer
+func textLayerSample(pages []Page) string {
+	var b strings.Builder
+	for _, page := range pages {
+		if page.Method == MethodText {
+			b.WriteString(page.Text)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// usableTextLayer menolak text layer yang kosong atau rusak, misalnya font tanpa
+// ToUnicode map yang menghasilkan karakter pengganti atau simbol acak
+func usableTextLayer(text string) bool {
+	var total, readable, broken int
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		total++
+		switch {
+		case r == unicode.ReplacementChar, unicode.IsControl(r), unicode.In(r, unicode.Co):
+			broken++
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			readable++
+		}
+	}
+	if total < minTextLayerChars {
+		return false
+	}
+	// Lebih dari 5% karakter rusak atau kurang dari separuh berupa huruf/angka
+	return broken*20 <= total && readable*2 >= total
+}
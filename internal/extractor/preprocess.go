@@ -0,0 +1,163 @@
+package extractor
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+const (
+	maxSkewDegrees  = 5.0
+	skewStepDegrees = 0.5
+	// skewSampleWidth membatasi resolusi sampel saat mengestimasi kemiringan
+	skewSampleWidth = 800
+)
+
+// preprocessOptions mengatur tahap preprocessing sebelum OCR
+type preprocessOptions struct {
+	Deskew   bool
+	Binarize bool
+}
+
+// preprocess menyiapkan gambar halaman untuk OCR: grayscale, luruskan kemiringan
+// hasil scan (deskew), lalu binarisasi dengan threshold Otsu
+func preprocess(img image.Image, opts preprocessOptions) image.Image {
+	gray := toGray(img)
+	if opts.Deskew {
+		if angle := estimateSkew(gray); math.Abs(angle) >= skewStepDegrees {
+			gray = rotateGray(gray, -angle)
+		}
+	}
+	if opts.Binarize {
+		binarize(gray, otsuThreshold(gray))
+	}
+	return gray
+}
+
+// toGray selalu mengembalikan salinan: binarize mengubah Pix di tempat, dan
+// gambar yang sama bisa di-OCR lebih dari sekali (sampel deteksi bahasa)
+func toGray(img image.Image) *image.Gray {
+	b := img.Bounds()
+	gray := image.NewGray(image.Rect(0, 0, b.Dx(), b.Dy()))
+	if src, ok := img.(*image.Gray); ok {
+		for y := 0; y < b.Dy(); y++ {
+			start := src.PixOffset(b.Min.X, b.Min.Y+y)
+			copy(gray.Pix[y*gray.Stride:y*gray.Stride+b.Dx()], src.Pix[start:start+b.Dx()])
+		}
+		return gray
+	}
+	draw.Draw(gray, gray.Bounds(), img, b.Min, draw.Src)
+	return gray
+}
+
+// otsuThreshold mencari threshold yang memaksimalkan variansi antar kelas
+// (teks vs background) dari histogram grayscale
+func otsuThreshold(gray *image.Gray) uint8 {
+	var hist [256]int
+	for _, v := range gray.Pix {
+		hist[v]++
+	}
+	total := len(gray.Pix)
+	var sum float64
+	for i, count := range hist {
+		sum += float64(i * count)
+	}
+
+	var sumBackground float64
+	var weightBackground int
+	var best float64
+	threshold := uint8(128)
+	for i, count := range hist {
+		weightBackground += count
+		if weightBackground == 0 {
+			continue
+		}
+		weightForeground := total - weightBackground
+		if weightForeground == 0 {
+			break
+		}
+		sumBackground += float64(i * count)
+		meanBackground := sumBackground / float64(weightBackground)
+		meanForeground := (sum - sumBackground) / float64(weightForeground)
+		between := float64(weightBackground) * float64(weightForeground) * (meanBackground - meanForeground) * (meanBackground - meanForeground)
+		if between > best {
+			best = between
+			threshold = uint8(i)
+		}
+	}
+	return threshold
+}
+
+func binarize(gray *image.Gray, threshold uint8) {
+	for i, v := range gray.Pix {
+		if v > threshold {
+			gray.Pix[i] = 255
+		} else {
+			gray.Pix[i] = 0
+		}
+	}
+}
+
+// estimateSkew mengestimasi kemiringan teks (derajat) dengan projection profile:
+// pada sudut yang benar, piksel gelap terkumpul di baris-baris teks sehingga
+// jumlah kuadrat histogram proyeksi horizontal paling besar
+func estimateSkew(gray *image.Gray) float64 {
+	b := gray.Bounds()
+	step := max(1, b.Dx()/skewSampleWidth)
+
+	type point struct{ x, y float64 }
+	var points []point
+	for y := b.Min.Y; y < b.Max.Y; y += step {
+		for x := b.Min.X; x < b.Max.X; x += step {
+			if gray.GrayAt(x, y).Y < 128 {
+				points = append(points, point{float64(x), float64(y)})
+			}
+		}
+	}
+	if len(points) < 100 {
+		return 0
+	}
+
+	best, bestScore := 0.0, -1.0
+	for angle := -maxSkewDegrees; angle <= maxSkewDegrees; angle += skewStepDegrees {
+		rad := angle * math.Pi / 180
+		sin, cos := math.Sin(rad), math.Cos(rad)
+		bins := map[int]int{}
+		for _, p := range points {
+			bins[int((p.y*cos-p.x*sin)/float64(step))]++
+		}
+		var score float64
+		for _, count := range bins {
+			score += float64(count * count)
+		}
+		if score > bestScore {
+			best, bestScore = angle, score
+		}
+	}
+	return best
+}
+
+// rotateGray memutar gambar terhadap titik tengahnya (nearest neighbour);
+// area kosong diisi putih
+func rotateGray(gray *image.Gray, degrees float64) *image.Gray {
+	b := gray.Bounds()
+	out := image.NewGray(b)
+	rad := degrees * math.Pi / 180
+	sin, cos := math.Sin(rad), math.Cos(rad)
+	cx, cy := float64(b.Min.X+b.Max.X)/2, float64(b.Min.Y+b.Max.Y)/2
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dx, dy := float64(x)-cx, float64(y)-cy
+			sx := int(math.Round(dx*cos + dy*sin + cx))
+			sy := int(math.Round(-dx*sin + dy*cos + cy))
+			if image.Pt(sx, sy).In(b) {
+				out.SetGray(x, y, gray.GrayAt(sx, sy))
+			} else {
+				out.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return out
+}
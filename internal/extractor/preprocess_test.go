@@ -0,0 +1,41 @@
+package extractor
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPreprocessDoesNotModifyInput(t *testing.T) {
+	// Gambar grayscale dengan bounds yang tidak mulai dari (0,0), seperti hasil SubImage
+	src := image.NewGray(image.Rect(0, 0, 40, 30))
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			src.SetGray(x, y, color.Gray{Y: uint8(60 + (x*5+y*3)%150)})
+		}
+	}
+	img := src.SubImage(image.Rect(5, 4, 35, 26)).(*image.Gray)
+	before := bytes.Clone(src.Pix)
+
+	first := preprocess(img, preprocessOptions{Binarize: true}).(*image.Gray)
+	if !bytes.Equal(src.Pix, before) {
+		t.Fatal("preprocess modified the input image")
+	}
+	second := preprocess(img, preprocessOptions{Binarize: true}).(*image.Gray)
+	if !bytes.Equal(first.Pix, second.Pix) {
+		t.Error("second pass on the same image gave a different result")
+	}
+
+	if got := first.Bounds(); got != image.Rect(0, 0, 30, 22) {
+		t.Errorf("bounds = %v, want 30x22 from origin", got)
+	}
+	gray := toGray(img)
+	for y := 0; y < 22; y++ {
+		for x := 0; x < 30; x++ {
+			if gray.GrayAt(x, y) != img.GrayAt(x+5, y+4) {
+				t.Fatalf("pixel (%d,%d) = %v, want %v", x, y, gray.GrayAt(x, y), img.GrayAt(x+5, y+4))
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package extractor
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ExtractText membaca file teks biasa atau Markdown
+func ExtractText(_ context.Context, path string, _ Options) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	return singlePage(text, MethodText, 100), nil
+}
+
+// ExtractRTF mengambil teks dari RTF dengan membuang control word dan
+// destination yang bukan isi dokumen (font table, style, gambar, metadata)
+func ExtractRTF(_ context.Context, path string, _ Options) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return singlePage(rtfToText(data), MethodText, 100), nil
+}
+
+// rtfSkipDestinations adalah group RTF yang isinya bukan teks dokumen
+var rtfSkipDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "object": true, "header": true, "footer": true,
+	"headerl": true, "headerr": true, "footerl": true, "footerr": true,
+	"themedata": true, "colorschememapping": true, "latentstyles": true,
+	"datastore": true, "listtable": true, "listoverridetable": true,
+	"rsidtbl": true, "generator": true, "xmlnstbl": true, "filetbl": true,
+}
+
+var rtfSymbols = map[string]string{
+	"par": "\n", "line": "\n", "sect": "\n", "page": "\n", "row": "\n",
+	"tab": "\t", "cell": "\t",
+	"emdash": "—", "endash": "–", "bullet": "•",
+	"lquote": "'", "rquote": "'", "ldblquote": "\"", "rdblquote": "\"",
+}
+
+func rtfToText(data []byte) string {
+	type group struct {
+		skip bool
+		uc   int // jumlah karakter fallback setelah \uN
+	}
+
+	var b strings.Builder
+	stack := []group{{uc: 1}}
+	pendingSkip := 0 // karakter fallback \uN yang belum dilewati
+
+	emit := func(s string) {
+		if stack[len(stack)-1].skip {
+			return
+		}
+		if pendingSkip > 0 {
+			pendingSkip--
+			return
+		}
+		b.WriteString(s)
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch c {
+		case '{':
+			stack = append(stack, stack[len(stack)-1])
+			i++
+		case '}':
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+			pendingSkip = 0
+			i++
+		case '\r', '\n':
+			i++
+		case '\\':
+			i++
+			if i >= len(data) {
+				break
+			}
+			c = data[i]
+			switch {
+			case c == '\\' || c == '{' || c == '}':
+				emit(string(c))
+				i++
+			case c == '\'':
+				// \'hh: karakter dari code page dokumen, diperlakukan sebagai Latin-1
+				if i+2 < len(data) {
+					if v, err := strconv.ParseUint(string(data[i+1:i+3]), 16, 8); err == nil {
+						emit(string(rune(v)))
+					}
+				}
+				i += 3
+			case c == '*':
+				stack[len(stack)-1].skip = true
+				i++
+			case c == '~':
+				emit(" ")
+				i++
+			case c == '_':
+				emit("-")
+				i++
+			case c == '\r' || c == '\n':
+				emit("\n")
+				i++
+			case isASCIILetter(c):
+				start := i
+				for i < len(data) && isASCIILetter(data[i]) {
+					i++
+				}
+				word := string(data[start:i])
+
+				paramStart := i
+				if i < len(data) && data[i] == '-' {
+					i++
+				}
+				for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+					i++
+				}
+				param, hasParam := 0, i > paramStart
+				if hasParam {
+					param, _ = strconv.Atoi(string(data[paramStart:i]))
+				}
+				if i < len(data) && data[i] == ' ' {
+					i++
+				}
+
+				switch {
+				case word == "u" && hasParam:
+					if param < 0 {
+						param += 65536
+					}
+					emit(string(rune(param)))
+					if !stack[len(stack)-1].skip {
+						pendingSkip = stack[len(stack)-1].uc
+					}
+				case word == "uc" && hasParam:
+					stack[len(stack)-1].uc = param
+				case rtfSkipDestinations[word]:
+					stack[len(stack)-1].skip = true
+				case rtfSymbols[word] != "":
+					emit(rtfSymbols[word])
+				}
+			default:
+				// control symbol lain (mis. \- optional hyphen) diabaikan
+				i++
+			}
+		default:
+			// RTF seharusnya 7-bit; byte di atas ASCII dibaca sebagai UTF-8 kalau valid,
+			// selain itu sebagai Latin-1
+			if r, size := utf8.DecodeRune(data[i:]); r != utf8.RuneError && size > 1 {
+				emit(string(r))
+				i += size
+				continue
+			}
+			emit(string(rune(c)))
+			i++
+		}
+	}
+	return b.String()
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,183 @@
+package extractor
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+	"github.com/gen2brain/go-fitz"
+)
+
+// Error validasi file upload. Semuanya membungkus ErrInvalidFile, jadi cukup
+// dicek dengan errors.Is(err, ErrInvalidFile) untuk membalas 400.
+var (
+	ErrInvalidFile     = errors.New("invalid file")
+	ErrTypeMismatch    = fmt.Errorf("%w: file content does not match its extension", ErrInvalidFile)
+	ErrEncryptedPDF    = fmt.Errorf("%w: PDF is encrypted or password-protected, upload it without a password", ErrInvalidFile)
+	ErrTooManyPages    = fmt.Errorf("%w: too many pages", ErrInvalidFile)
+	ErrImageTooLarge   = fmt.Errorf("%w: image dimensions are too large", ErrInvalidFile)
+	ErrArchiveTooLarge = fmt.Errorf("%w: document content is too large when uncompressed", ErrInvalidFile)
+)
+
+// extensionTypes adalah MIME type yang diharapkan untuk tiap ekstensi file
+var extensionTypes = map[string]string{
+	".pdf":      MIMEPDF,
+	".docx":     MIMEDOCX,
+	".odt":      MIMEODT,
+	".txt":      MIMEText,
+	".md":       MIMEMarkdown,
+	".markdown": MIMEMarkdown,
+	".rtf":      MIMERTF,
+	".png":      MIMEPNG,
+	".jpg":      MIMEJPEG,
+	".jpeg":     MIMEJPEG,
+}
+
+// Validate memastikan file upload aman untuk diekstrak: MIME type dari isi file
+// didukung dan sesuai ekstensinya, PDF tidak terenkripsi dan jumlah & ukuran
+// halamannya dalam batas, gambar dan arsip DOCX/ODT tidak terlalu besar setelah
+// di-decode. Mengembalikan MIME type file.
+func Validate(path, filename string) (string, error) {
+	mimeType, err := Detect(path, filename)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if !Supported(mimeType) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, mimeType)
+	}
+
+	// File tanpa ekstensi diterima sesuai isinya; ekstensi lain harus cocok
+	if ext := strings.ToLower(filepath.Ext(filename)); ext != "" && extensionTypes[ext] != mimeType {
+		return "", fmt.Errorf("%w: %s file contains %s", ErrTypeMismatch, ext, mimeType)
+	}
+
+	switch mimeType {
+	case MIMEPDF:
+		err = validatePDF(path)
+	case MIMEDOCX, MIMEODT:
+		err = validateArchive(path)
+	case MIMEPNG, MIMEJPEG:
+		err = validateImage(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return mimeType, nil
+}
+
+func validatePDF(path string) error {
+	doc, err := openPDF(path)
+	if err != nil {
+		return err
+	}
+	defer doc.Close()
+
+	if doc.NumPage() == 0 {
+		return fmt.Errorf("%w: PDF has no pages", ErrInvalidFile)
+	}
+	if err := checkPageCount(doc.NumPage()); err != nil {
+		return err
+	}
+	for n := 0; n < doc.NumPage(); n++ {
+		if err := checkPageSize(doc, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openPDF membuka PDF dan menerjemahkan PDF berpassword ke ErrEncryptedPDF
+func openPDF(path string) (*fitz.Document, error) {
+	doc, err := fitz.New(path)
+	if errors.Is(err, fitz.ErrNeedsPassword) {
+		return nil, ErrEncryptedPDF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot open PDF: %v", ErrInvalidFile, err)
+	}
+	return doc, nil
+}
+
+func checkPageCount(pages int) error {
+	if limit := config.LoadUploadConfig().MaxPages; pages > limit {
+		return fmt.Errorf("%w: %d pages, max %d", ErrTooManyPages, pages, limit)
+	}
+	return nil
+}
+
+// checkPageSize menolak halaman yang hasil render-nya pada OCR_DPI melebihi
+// UPLOAD_MAX_IMAGE_PIXELS. Ukuran halaman PDF dalam point (1/72 inch).
+func checkPageSize(doc *fitz.Document, n int) error {
+	bound, err := doc.Bound(n)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read page %d: %v", ErrInvalidFile, n+1, err)
+	}
+	scale := config.LoadOCRConfig().DPI / 72
+	width := int(float64(bound.Dx()) * scale)
+	height := int(float64(bound.Dy()) * scale)
+	if err := checkPixels(width, height); err != nil {
+		return fmt.Errorf("page %d: %w", n+1, err)
+	}
+	return nil
+}
+
+func checkPixels(width, height int) error {
+	limit := config.LoadUploadConfig().MaxImagePixels
+	if width <= 0 || height <= 0 || int64(width)*int64(height) > int64(limit) {
+		return fmt.Errorf("%w: %dx%d pixels, max %d pixels", ErrImageTooLarge, width, height, limit)
+	}
+	return nil
+}
+
+// validateImage membaca dimensi dari header gambar tanpa men-decode seluruh isinya
+func validateImage(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return fmt.Errorf("%w: cannot read image: %v", ErrInvalidFile, err)
+	}
+	return checkPixels(cfg.Width, cfg.Height)
+}
+
+// validateArchive menolak zip bomb berdasarkan ukuran uncompressed di header
+// arsip; readZipEntry tetap membatasi jumlah byte yang benar-benar dibaca
+func validateArchive(path string) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("%w: invalid zip archive: %v", ErrInvalidFile, err)
+	}
+	defer r.Close()
+
+	limit := uint64(config.LoadUploadConfig().MaxUncompressedSize)
+	var total uint64
+	for _, f := range r.File {
+		total += f.UncompressedSize64
+		if total > limit {
+			return fmt.Errorf("%w: more than %d bytes", ErrArchiveTooLarge, limit)
+		}
+	}
+	return nil
+}
+
+// limitedReadAll membaca r sampai limit byte; lebih dari itu dianggap ErrArchiveTooLarge
+func limitedReadAll(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrArchiveTooLarge, limit)
+	}
+	return data, nil
+}
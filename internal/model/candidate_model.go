@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Candidate adalah orang yang mengirim CV, dikenali dari email atau nomor
+// telepon yang sudah dinormalisasi. Semua task dari orang yang sama ditautkan
+// ke satu candidate lewat EvaluationTask.CandidateID.
+type Candidate struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Email     string    `gorm:"type:varchar(255);index:idx_candidates_email,unique,where:email <> ''" json:"email"` // lowercase
+	Phone     string    `gorm:"type:varchar(20);index:idx_candidates_phone,unique,where:phone <> ''" json:"phone"`  // digit saja, format 62xxx
+	Name      string    `gorm:"type:varchar(255)" json:"name"`                                                      // nama dari CV terbaru
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (c *Candidate) TableName() string {
+	return "candidates"
+}
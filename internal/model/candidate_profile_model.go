@@ -0,0 +1,53 @@
+package model
+
+// CandidateProfile adalah hasil parsing CV yang terstruktur (stage extract),
+// disimpan sebagai JSONB di evaluation_tasks.profile supaya bisa difilter.
+// Tanggal ditulis seperti di CV ("2021-03", "Mar 2021", "Present").
+type CandidateProfile struct {
+	Name           string           `json:"name"`
+	Contact        Contact          `json:"contact"`
+	Location       string           `json:"location"`
+	Summary        string           `json:"summary"`
+	Education      []Education      `json:"education"`
+	Experiences    []WorkExperience `json:"experiences"`
+	Skills         []string         `json:"skills"`
+	Certifications []Certification  `json:"certifications"`
+	Languages      []string         `json:"languages"` // bahasa yang dikuasai kandidat
+	Links          []string         `json:"links"`     // portfolio, GitHub, LinkedIn, dsb.
+	Projects       []Project        `json:"projects"`
+	Achievements   []string         `json:"achievements"`
+}
+
+type Contact struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+type Education struct {
+	Institution string `json:"institution"`
+	Degree      string `json:"degree"`
+	Field       string `json:"field"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+}
+
+type WorkExperience struct {
+	Title       string `json:"title"`
+	Company     string `json:"company"`
+	Location    string `json:"location"`
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Description string `json:"description"`
+}
+
+type Certification struct {
+	Name   string `json:"name"`
+	Issuer string `json:"issuer"`
+	Date   string `json:"date"`
+}
+
+type Project struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Technologies []string `json:"technologies"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+)
+
+// DocumentExtraction adalah cache hasil ekstraksi teks per hash file. Key berisi
+// versi extractor & setting OCR (extractor.CacheKey), jadi perubahan setting
+// tidak memakai hasil lama.
+type DocumentExtraction struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Hash      string    `gorm:"type:varchar(64);uniqueIndex:idx_document_extractions_hash_key" json:"hash"`
+	Key       string    `gorm:"type:varchar(255);uniqueIndex:idx_document_extractions_hash_key" json:"key"`
+	Text      string    `gorm:"type:text" json:"text"`
+	Result    string    `gorm:"type:jsonb;default:'{}'" json:"result"` // extractor.Document tanpa teks
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (e *DocumentExtraction) TableName() string {
+	return "document_extractions"
+}
+
+// DocumentEmbedding adalah cache embedding dokumen per hash file, model embedding
+// dan key ekstraksi (extractor.CacheKey), karena teks yang di-embed berubah kalau
+// setting OCR berubah.
+type DocumentEmbedding struct {
+	ID            uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Hash          string          `gorm:"type:varchar(64);uniqueIndex:idx_document_embeddings_hash_model_key" json:"hash"`
+	Model         string          `gorm:"type:varchar(255);uniqueIndex:idx_document_embeddings_hash_model_key" json:"model"`
+	ExtractionKey string          `gorm:"type:varchar(255);uniqueIndex:idx_document_embeddings_hash_model_key" json:"extraction_key"`
+	Embedding     pgvector.Vector `gorm:"type:vector(3072)" json:"-"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func (e *DocumentEmbedding) TableName() string {
+	return "document_embeddings"
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Document adalah file yang di-upload. File disimpan di storage dengan key dari
+// hash isinya (StorageKey), jadi upload dengan nama sama tidak saling menimpa dan
+// file yang isinya sama hanya disimpan sekali.
+type Document struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Hash       string    `gorm:"type:varchar(64);index" json:"hash"` // SHA-256 isi file (hex)
+	StorageKey string    `gorm:"type:text" json:"storage_key"`
+	Filename   string    `gorm:"type:varchar(255)" json:"filename"` // nama file asli (sudah disanitasi), hanya untuk ditampilkan
+	Size       int64     `json:"size"`
+	MIMEType   string    `gorm:"type:varchar(100)" json:"mime_type"` // dideteksi dari isi file
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (d *Document) TableName() string {
+	return "documents"
+}
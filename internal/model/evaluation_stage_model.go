@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Urutan stage pipeline evaluasi
+const (
+	StageExtract           = "extract"
+	StageCVEvaluation      = "cv_evaluation"
+	StageProjectEvaluation = "project_evaluation"
+	StageSynthesis         = "synthesis"
+
+	StageStatusCompleted = "completed"
+	StageStatusFailed    = "failed"
+)
+
+// EvaluationStage menyimpan input & output satu stage pipeline evaluasi, supaya
+// retry bisa melanjutkan dari stage yang gagal tanpa mengulang stage sebelumnya
+type EvaluationStage struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskID    uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_evaluation_stages_task_stage" json:"task_id"`
+	Stage     string    `gorm:"type:varchar(50);uniqueIndex:idx_evaluation_stages_task_stage" json:"stage"`
+	Status    string    `gorm:"type:varchar(50)" json:"status"`        // e.g. "completed", "failed"
+	Input     string    `gorm:"type:text" json:"input"`                // prompt yang dikirim ke LLM
+	Output    string    `gorm:"type:jsonb;default:'{}'" json:"output"` // output yang sudah tervalidasi
+	Attempts  int       `gorm:"default:0" json:"attempts"`
+	Provider  string    `gorm:"type:varchar(50)" json:"provider"`
+	Model     string    `gorm:"type:varchar(100)" json:"model"`
+	Error     string    `gorm:"type:text" json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (s *EvaluationStage) TableName() string {
+	return "evaluation_stages"
+}
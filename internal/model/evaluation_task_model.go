@@ -6,17 +6,70 @@ import (
 	"github.com/google/uuid"
 )
 
+// Status task mengikuti stage pipeline: file tersimpan (uploaded), ekstraksi teks,
+// embedding & pencarian konteks, evaluasi LLM, lalu completed atau failed
+const (
+	TaskStatusUploaded   = "uploaded"
+	TaskStatusExtracting = "extracting"
+	TaskStatusEmbedding  = "embedding"
+	TaskStatusEvaluating = "evaluating"
+	TaskStatusCompleted  = "completed"
+	TaskStatusFailed     = "failed"
+)
+
 type EvaluationTask struct {
-	ID              uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	CV              string    `gorm:"type:text" json:"cv"`
-	Report          string    `gorm:"type:text" json:"report"`
-	Status          string    `gorm:"type:varchar(50)" json:"status"` // e.g. "processing", "completed", "failed"
-	CvMatchRate     float64   `gorm:"type:float" json:"cv_match_rate"`
-	CvFeedback      string    `gorm:"type:text" json:"cv_feedback"`
-	ProjectScore    float64   `gorm:"type:float" json:"project_score"`
-	ProjectFeedback string    `gorm:"type:text" json:"project_feedback"`
-	OverallSummary  string    `gorm:"type:text" json:"overall_summary"`
-	Breakdown       string    `gorm:"type:jsonb" json:"breakdown"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID                uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	JobID             *uuid.UUID        `gorm:"type:uuid;index" json:"job_id"`         // optional, kosong = pakai RAG top-5
+	CandidateID       *uuid.UUID        `gorm:"type:uuid;index" json:"candidate_id"`   // diisi setelah CV diparsing, kosong kalau CV tanpa email & telepon
+	RubricID          *uuid.UUID        `gorm:"type:uuid;index" json:"rubric_id"`      // versi rubric yang dipakai
+	CVDocumentID      *uuid.UUID        `gorm:"type:uuid;index" json:"cv_document_id"` // file yang di-upload, diekstrak oleh worker
+	ReportDocumentID  *uuid.UUID        `gorm:"type:uuid;index" json:"report_document_id"`
+	OCRLanguages      string            `gorm:"type:varchar(50)" json:"ocr_languages"` // bahasa OCR dari request, kosong = deteksi otomatis
+	Language          string            `gorm:"type:varchar(10)" json:"language"`      // bahasa CV hasil deteksi (ISO 639-1)
+	CV                string            `gorm:"type:text" json:"cv"`
+	Report            string            `gorm:"type:text" json:"report"`
+	Profile           *CandidateProfile `gorm:"type:jsonb;serializer:json" json:"profile"`   // hasil parsing CV (stage extract)
+	Timeline          *Timeline         `gorm:"type:jsonb;serializer:json" json:"timeline"`  // riwayat kerja yang dihitung dari Profile
+	SkillGap          *SkillGapReport   `gorm:"type:jsonb;serializer:json" json:"skill_gap"` // skill kandidat vs skill job (matched/missing/extra)
+	Extraction        string            `gorm:"type:jsonb;default:'{}'" json:"extraction"`   // metode ekstraksi per halaman (text layer / OCR)
+	ExtractionQuality float64           `gorm:"type:float" json:"extraction_quality"`        // kualitas ekstraksi terendah dari CV & report (0-1)
+	LowQuality        bool              `gorm:"default:false" json:"low_quality"`            // kualitas di bawah OCR_MIN_QUALITY, hasil evaluasi perlu dicek manual
+	Status            string            `gorm:"type:varchar(50)" json:"status"`              // e.g. "extracting", "evaluating", "completed", "failed"
+	CvMatchRate       float64           `gorm:"type:float" json:"cv_match_rate"`
+	CvFeedback        string            `gorm:"type:text" json:"cv_feedback"`
+	ProjectScore      float64           `gorm:"type:float" json:"project_score"`
+	ProjectFeedback   string            `gorm:"type:text" json:"project_feedback"`
+	OverallSummary    string            `gorm:"type:text" json:"overall_summary"`
+	Breakdown         string            `gorm:"type:jsonb" json:"breakdown"`
+	ScoreDetails      string            `gorm:"type:jsonb;default:'{}'" json:"score_details"` // skor mentah + perhitungan berbobot
+	ContextJobs       string            `gorm:"type:jsonb;default:'[]'" json:"context_jobs"`  // job yang dipakai sebagai konteks evaluasi
+	ResultKey         string            `gorm:"type:varchar(64);index" json:"result_key"`     // hash (CV, report, job, rubric, model) untuk reuse hasil evaluasi
+	ReusedFromID      *uuid.UUID        `gorm:"type:uuid" json:"reused_from_id"`              // task asal kalau hasil evaluasi disalin
+	Provider          string            `gorm:"type:varchar(100)" json:"provider"`            // provider LLM yang menghasilkan evaluasi (semua stage, dipisah koma)
+	Model             string            `gorm:"type:varchar(255)" json:"model"`
+	Error             string            `gorm:"type:text" json:"error"` // alasan kalau status "failed"
+	StatusHistory     []StatusChange    `gorm:"type:jsonb;serializer:json;default:'[]'" json:"status_history"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+// ContextJob adalah ringkasan job yang dipakai sebagai konteks evaluasi (disimpan di ContextJobs)
+type ContextJob struct {
+	ID    uuid.UUID `json:"id"`
+	Title string    `json:"title"`
+}
+
+// StatusChange mencatat kapan task masuk ke suatu status
+type StatusChange struct {
+	Status string    `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// SetStatus mengubah status task dan mencatatnya di StatusHistory
+func (t *EvaluationTask) SetStatus(status string) {
+	if t.Status == status {
+		return
+	}
+	t.Status = status
+	t.StatusHistory = append(t.StatusHistory, StatusChange{Status: status, At: time.Now()})
 }
@@ -8,12 +8,17 @@ import (
 )
 
 type Job struct {
-	ID        uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Title     string          `json:"title"`
-	Content   string          `gorm:"type:text" json:"content"`
-	Embedding pgvector.Vector `gorm:"type:vector(3072)" json:"embedding"` // pakai pgvector
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID      uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Title   string    `json:"title"`
+	Content string    `gorm:"type:text" json:"content"`
+	// CaseStudyBrief adalah brief studi kasus untuk menilai project report; kosong = brief default
+	CaseStudyBrief string `gorm:"type:text" json:"case_study_brief"`
+	// Skill yang diekstrak dari Content dengan taxonomy skill (lihat package skills)
+	RequiredSkills   []string        `gorm:"type:jsonb;serializer:json" json:"required_skills"`
+	NiceToHaveSkills []string        `gorm:"type:jsonb;serializer:json" json:"nice_to_have_skills"`
+	Embedding        pgvector.Vector `gorm:"type:vector(3072)" json:"embedding"` // pakai pgvector
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
 }
 
 func (j *Job) TableName() string {
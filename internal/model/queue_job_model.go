@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	QueueStatusQueued     = "queued"
+	QueueStatusProcessing = "processing"
+	QueueStatusDone       = "done"
+	QueueStatusFailed     = "failed"
+
+	QueueKindEvaluate = "evaluate"
+)
+
+// QueueJob adalah satu unit kerja di antrian Postgres yang di-claim oleh worker
+type QueueJob struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TaskID      uuid.UUID  `gorm:"type:uuid;index" json:"task_id"`
+	Kind        string     `gorm:"type:varchar(50);index" json:"kind"`
+	Status      string     `gorm:"type:varchar(50);index" json:"status"` // e.g. "queued", "processing", "done", "failed"
+	Attempts    int        `gorm:"default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"default:3" json:"max_attempts"`
+	RunAt       time.Time  `gorm:"index" json:"run_at"`
+	LockedBy    string     `gorm:"type:varchar(100)" json:"locked_by"`
+	LeaseUntil  *time.Time `json:"lease_until"`
+	LastError   string     `gorm:"type:text" json:"last_error"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (q *QueueJob) TableName() string {
+	return "queue_jobs"
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RubricSectionCV            = "cv"
+	RubricSectionProjectReport = "project_report"
+)
+
+// Rubric adalah satu versi rubric penilaian. Versi lama tidak diubah, sehingga
+// evaluasi lama tetap bisa diaudit terhadap rubric yang dipakai saat itu.
+type Rubric struct {
+	ID                uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	GroupID           uuid.UUID         `gorm:"type:uuid;index" json:"group_id"` // sama untuk semua versi rubric yang sama
+	Version           int               `json:"version"`
+	Name              string            `json:"name"`
+	JobID             *uuid.UUID        `gorm:"type:uuid;index" json:"job_id"` // kosong = rubric default
+	Active            bool              `gorm:"index" json:"active"`
+	CVParameters      []RubricParameter `gorm:"type:jsonb;serializer:json" json:"cv"`
+	ProjectParameters []RubricParameter `gorm:"type:jsonb;serializer:json" json:"project_report"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+type RubricParameter struct {
+	Key         string  `json:"key"`
+	Name        string  `json:"name"`
+	Weight      float64 `json:"weight"` // persen, total per section = 100
+	Description string  `json:"description"`
+}
+
+func (r *Rubric) TableName() string {
+	return "rubrics"
+}
+
+// Section mengembalikan parameter untuk section "cv" atau "project_report"
+func (r *Rubric) Section(section string) []RubricParameter {
+	switch section {
+	case RubricSectionCV:
+		return r.CVParameters
+	case RubricSectionProjectReport:
+		return r.ProjectParameters
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import "github.com/google/uuid"
+
+// SkillGapReport membandingkan skill kandidat dengan skill tiap job konteks.
+// Semua nama skill sudah dinormalisasi dengan taxonomy (lihat package skills).
+type SkillGapReport struct {
+	CandidateSkills []string      `json:"candidate_skills"`
+	Jobs            []JobSkillGap `json:"jobs"`
+}
+
+// JobSkillGap adalah gap skill kandidat terhadap satu job
+type JobSkillGap struct {
+	JobID             uuid.UUID `json:"job_id"`
+	JobTitle          string    `json:"job_title"`
+	Matched           []string  `json:"matched"` // skill wajib yang dimiliki
+	Missing           []string  `json:"missing"` // skill wajib yang tidak ada
+	MatchedNiceToHave []string  `json:"matched_nice_to_have"`
+	MissingNiceToHave []string  `json:"missing_nice_to_have"`
+	Extra             []string  `json:"extra"`    // skill kandidat yang tidak disebut job
+	Coverage          float64   `json:"coverage"` // porsi skill wajib yang dimiliki (0-1), 1 kalau job tidak punya skill wajib
+}
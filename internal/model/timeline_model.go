@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Timeline adalah riwayat kerja kandidat yang dihitung deterministik dari
+// CandidateProfile.Experiences (lihat package timeline), bukan ditebak LLM
+type Timeline struct {
+	Stints         []Stint   `json:"stints"`
+	Unparsed       []string  `json:"unparsed"` // pengalaman yang tanggalnya tidak terbaca
+	TotalMonths    int       `json:"total_months"`
+	TotalYears     float64   `json:"total_years"`
+	RelevantMonths int       `json:"relevant_months"`
+	RelevantYears  float64   `json:"relevant_years"`
+	Gaps           []Gap     `json:"gaps"`
+	ComputedAt     time.Time `json:"computed_at"` // acuan "Present"
+}
+
+// Stint adalah satu pengalaman kerja dengan tanggal yang sudah dinormalisasi
+// ke awal bulan; End inklusif
+type Stint struct {
+	Title    string    `json:"title"`
+	Company  string    `json:"company"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Current  bool      `json:"current"`
+	Months   int       `json:"months"`
+	Relevant bool      `json:"relevant"`
+}
+
+// Gap adalah jeda tanpa pekerjaan di antara pengalaman (atau sampai sekarang)
+type Gap struct {
+	Start  time.Time `json:"start"` // bulan pertama tanpa pekerjaan
+	End    time.Time `json:"end"`   // bulan terakhir tanpa pekerjaan
+	Months int       `json:"months"`
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CandidateRepository struct {
+	db *gorm.DB
+}
+
+func NewCandidateRepository(db *gorm.DB) *CandidateRepository {
+	return &CandidateRepository{db}
+}
+
+// Create menyimpan candidate baru. Kalau email/telepon sudah dipakai candidate
+// lain (dibuat worker lain bersamaan), tidak ada yang ditulis dan created = false.
+func (r *CandidateRepository) Create(candidate *model.Candidate) (bool, error) {
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(candidate)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *CandidateRepository) Update(candidate *model.Candidate) error {
+	return r.db.Save(candidate).Error
+}
+
+func (r *CandidateRepository) FindByID(id uuid.UUID) (*model.Candidate, error) {
+	var candidate model.Candidate
+	err := r.db.First(&candidate, "id = ?", id).Error
+	return &candidate, err
+}
+
+// FindByContact mencari candidate dengan email yang sama, lalu telepon yang
+// sama; email diutamakan kalau keduanya cocok dengan candidate berbeda
+func (r *CandidateRepository) FindByContact(email, phone string) (*model.Candidate, error) {
+	var candidate model.Candidate
+	if email != "" {
+		err := r.db.First(&candidate, "email = ?", email).Error
+		if err == nil || phone == "" {
+			return &candidate, err
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+	if phone == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	err := r.db.First(&candidate, "phone = ?", phone).Error
+	return &candidate, err
+}
+
+// CandidateFilter adalah filter daftar candidate; field kosong diabaikan
+type CandidateFilter struct {
+	Name  string // substring nama, case-insensitive
+	Email string // email yang sudah dinormalisasi
+	Phone string // telepon yang sudah dinormalisasi
+}
+
+// CandidateSummary adalah candidate beserta ringkasan evaluasinya
+type CandidateSummary struct {
+	model.Candidate
+	EvaluationCount  int64
+	LastEvaluationAt *time.Time
+}
+
+// ListCandidates mengambil candidate beserta jumlah & waktu evaluasi terakhir,
+// diurutkan dari yang terakhir dievaluasi
+func (r *CandidateRepository) ListCandidates(filter CandidateFilter, offset, limit int) ([]CandidateSummary, int64, error) {
+	var candidates []CandidateSummary
+	var total int64
+
+	query := r.db.Model(&model.Candidate{})
+	if filter.Name != "" {
+		query = query.Where("candidates.name ILIKE ?", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.Email != "" {
+		query = query.Where("candidates.email = ?", filter.Email)
+	}
+	if filter.Phone != "" {
+		query = query.Where("candidates.phone = ?", filter.Phone)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := withEvaluationStats(query).
+		Order("last_evaluation_at DESC NULLS LAST, candidates.created_at DESC").
+		Offset(offset).Limit(limit).
+		Scan(&candidates).Error
+	return candidates, total, err
+}
+
+// FindSummaryByID mengambil satu candidate beserta ringkasan evaluasinya
+func (r *CandidateRepository) FindSummaryByID(id uuid.UUID) (*CandidateSummary, error) {
+	var candidates []CandidateSummary
+	err := withEvaluationStats(r.db.Model(&model.Candidate{}).Where("candidates.id = ?", id)).
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &candidates[0], nil
+}
+
+// withEvaluationStats menambahkan jumlah evaluasi & waktu evaluasi terakhir
+func withEvaluationStats(query *gorm.DB) *gorm.DB {
+	return query.
+		Select("candidates.*, COUNT(evaluation_tasks.id) AS evaluation_count, MAX(evaluation_tasks.created_at) AS last_evaluation_at").
+		Joins("LEFT JOIN evaluation_tasks ON evaluation_tasks.candidate_id = candidates.id").
+		Group("candidates.id")
+}
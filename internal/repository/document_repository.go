@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewDocumentRepository(db *gorm.DB) *DocumentRepository {
+	return &DocumentRepository{db}
+}
+
+func (r *DocumentRepository) Create(doc *model.Document) error {
+	return r.db.Create(doc).Error
+}
+
+func (r *DocumentRepository) FindByID(id uuid.UUID) (*model.Document, error) {
+	var doc model.Document
+	err := r.db.First(&doc, "id = ?", id).Error
+	return &doc, err
+}
+
+// FindByIDs mengambil beberapa dokumen sekaligus, dikembalikan per ID
+func (r *DocumentRepository) FindByIDs(ids []uuid.UUID) (map[uuid.UUID]model.Document, error) {
+	docs := map[uuid.UUID]model.Document{}
+	if len(ids) == 0 {
+		return docs, nil
+	}
+	var rows []model.Document
+	if err := r.db.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, doc := range rows {
+		docs[doc.ID] = doc
+	}
+	return docs, nil
+}
+
+// FindExtraction mengambil hasil ekstraksi yang di-cache untuk hash & key tersebut
+func (r *DocumentRepository) FindExtraction(hash, key string) (*model.DocumentExtraction, error) {
+	var extraction model.DocumentExtraction
+	err := r.db.First(&extraction, "hash = ? AND key = ?", hash, key).Error
+	return &extraction, err
+}
+
+// SaveExtraction menyimpan hasil ekstraksi; hash & key yang sama ditimpa
+func (r *DocumentRepository) SaveExtraction(extraction *model.DocumentExtraction) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}, {Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "result", "created_at"}),
+	}).Create(extraction).Error
+}
+
+// FindEmbedding mengambil embedding yang di-cache untuk hash, model & key ekstraksi tersebut
+func (r *DocumentRepository) FindEmbedding(hash, embeddingModel, extractionKey string) (*model.DocumentEmbedding, error) {
+	var embedding model.DocumentEmbedding
+	err := r.db.First(&embedding, "hash = ? AND model = ? AND extraction_key = ?", hash, embeddingModel, extractionKey).Error
+	return &embedding, err
+}
+
+// SaveEmbedding menyimpan embedding; hash, model & key ekstraksi yang sama ditimpa
+func (r *DocumentRepository) SaveEmbedding(embedding *model.DocumentEmbedding) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}, {Name: "model"}, {Name: "extraction_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"embedding", "created_at"}),
+	}).Create(embedding).Error
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EvaluationStageRepository struct {
+	db *gorm.DB
+}
+
+func NewEvaluationStageRepository(db *gorm.DB) *EvaluationStageRepository {
+	return &EvaluationStageRepository{db}
+}
+
+// FindByTask mengambil semua stage milik task sesuai urutan pembuatan
+func (r *EvaluationStageRepository) FindByTask(taskID uuid.UUID) ([]model.EvaluationStage, error) {
+	var stages []model.EvaluationStage
+	err := r.db.Where("task_id = ?", taskID).Order("created_at ASC").Find(&stages).Error
+	return stages, err
+}
+
+// Save menyimpan hasil stage; stage yang sama untuk task yang sama ditimpa
+func (r *EvaluationStageRepository) Save(stage *model.EvaluationStage) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "task_id"}, {Name: "stage"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "input", "output", "attempts", "provider", "model", "error", "updated_at"}),
+	}).Create(stage).Error
+}
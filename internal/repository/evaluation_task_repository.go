@@ -1,7 +1,11 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -26,3 +30,197 @@ func (r *EvaluationRepository) FindTaskByID(id string) (*model.EvaluationTask, e
 	err := r.db.First(&task, "id = ?", id).Error
 	return &task, err
 }
+
+// FindCompletedByResultKey mengambil task selesai terbaru dengan result key yang sama
+func (r *EvaluationRepository) FindCompletedByResultKey(key string, excludeID uuid.UUID) (*model.EvaluationTask, error) {
+	var task model.EvaluationTask
+	err := r.db.Where("result_key = ? AND status = ? AND id <> ?", key, model.TaskStatusCompleted, excludeID).
+		Order("updated_at DESC").
+		First(&task).Error
+	return &task, err
+}
+
+// ListTasksByCandidate mengambil semua task seorang candidate, dari yang terlama
+func (r *EvaluationRepository) ListTasksByCandidate(candidateID uuid.UUID) ([]model.EvaluationTask, error) {
+	var tasks []model.EvaluationTask
+	err := r.db.Omit("cv", "report").
+		Where("candidate_id = ?", candidateID).
+		Order("created_at ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// FindUnlinkedProfiles mengambil task yang profilnya sudah diparsing tapi belum
+// ditautkan ke candidate (mis. task dari sebelum ada tabel candidates)
+func (r *EvaluationRepository) FindUnlinkedProfiles() ([]model.EvaluationTask, error) {
+	var tasks []model.EvaluationTask
+	err := r.db.Select("id", "profile", "created_at").
+		Where("candidate_id IS NULL AND profile IS NOT NULL AND profile::text <> 'null'").
+		Order("created_at ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// SetCandidate menautkan task ke candidate tanpa menyentuh kolom lain
+func (r *EvaluationRepository) SetCandidate(taskID, candidateID uuid.UUID) error {
+	return r.db.Model(&model.EvaluationTask{}).Where("id = ?", taskID).UpdateColumn("candidate_id", candidateID).Error
+}
+
+// TaskFilter adalah filter daftar task; field kosong diabaikan
+type TaskFilter struct {
+	Status      string
+	JobID       *uuid.UUID
+	CandidateID *uuid.UUID
+	Name        string   // substring nama kandidat, case-insensitive
+	Email       string   // email kandidat, case-insensitive
+	Location    string   // substring lokasi kandidat, case-insensitive
+	Skills      []string // kandidat harus punya semua skill ini (case-insensitive)
+	Language    string   // bahasa yang dikuasai kandidat (case-insensitive)
+}
+
+// ListTasks mengambil task dengan filter dari kolom & profil kandidat (JSONB),
+// diurutkan dari yang terbaru
+func (r *EvaluationRepository) ListTasks(filter TaskFilter, offset, limit int) ([]model.EvaluationTask, int64, error) {
+	var tasks []model.EvaluationTask
+	var total int64
+
+	query := r.db.Model(&model.EvaluationTask{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.JobID != nil {
+		query = query.Where("job_id = ?", *filter.JobID)
+	}
+	if filter.CandidateID != nil {
+		query = query.Where("candidate_id = ?", *filter.CandidateID)
+	}
+	if filter.Name != "" {
+		query = query.Where("profile->>'name' ILIKE ?", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.Email != "" {
+		query = query.Where("lower(profile->'contact'->>'email') = lower(?)", filter.Email)
+	}
+	if filter.Location != "" {
+		query = query.Where("profile->>'location' ILIKE ?", "%"+escapeLike(filter.Location)+"%")
+	}
+	for _, skill := range filter.Skills {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(profile->'skills', '[]'::jsonb)) AS s(skill) WHERE lower(s.skill) = lower(?))", skill)
+	}
+	if filter.Language != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(profile->'languages', '[]'::jsonb)) AS l(language) WHERE lower(l.language) = lower(?))", filter.Language)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	// Teks CV & report tidak dibutuhkan untuk daftar
+	err := query.Omit("cv", "report").Order("created_at DESC").Offset(offset).Limit(limit).Find(&tasks).Error
+	return tasks, total, err
+}
+
+// escapeLike meng-escape wildcard LIKE supaya input user dicari apa adanya
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// rankScoreSQL adalah skor gabungan untuk ranking kandidat (0-10): rata-rata
+// cv_match_rate (0-1, dikali 10) dan project_score (0-10)
+const rankScoreSQL = "ROUND(((t.cv_match_rate * 10 + t.project_score) / 2)::numeric, 2)::float8"
+
+// RankFilter adalah filter ranking kandidat untuk satu job; field kosong diabaikan
+type RankFilter struct {
+	Status   string
+	MinScore *float64
+	From     *time.Time // created_at >= From
+	To       *time.Time // created_at < To
+	Skills   []string   // kandidat harus punya semua skill ini (nama kanonik, case-insensitive)
+	// EmbeddingModel menentukan embedding CV yang dibandingkan dengan embedding job
+	EmbeddingModel string
+	// VectorPrefilter hanya menyisakan kandidat dengan embedding CV paling mirip
+	// dengan job (TopK & MinSimilarity) sebelum di-ranking berdasarkan skor
+	VectorPrefilter bool
+	TopK            int
+	MinSimilarity   *float64
+}
+
+// RankedEvaluation adalah satu hasil ranking; Similarity kosong kalau
+// embedding CV belum tersimpan
+type RankedEvaluation struct {
+	TaskID     uuid.UUID
+	Score      float64
+	Similarity *float64
+}
+
+// RankForJob me-ranking evaluasi terbaru tiap kandidat untuk satu job dari skor
+// tertinggi. Yang dihitung adalah task dengan job_id job ini dan task tanpa
+// job_id (RAG) yang memakai job ini sebagai context job. Task tanpa candidate
+// dihitung sebagai kandidat tersendiri.
+func (r *EvaluationRepository) RankForJob(jobID uuid.UUID, filter RankFilter, offset, limit int) ([]RankedEvaluation, int64, error) {
+	contextJob := `[{"id": "` + jobID.String() + `"}]`
+	latest := r.db.Table("evaluation_tasks").
+		Select("DISTINCT ON (COALESCE(candidate_id, id)) id").
+		Where("job_id = ? OR (job_id IS NULL AND context_jobs @> ?::jsonb)", jobID, contextJob)
+	if filter.Status != "" {
+		latest = latest.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		latest = latest.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		latest = latest.Where("created_at < ?", *filter.To)
+	}
+	for _, skill := range filter.Skills {
+		latest = latest.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(skill_gap->'candidate_skills', profile->'skills', '[]'::jsonb)) AS s(skill) WHERE lower(s.skill) = lower(?))", skill)
+	}
+	latest = latest.Order("COALESCE(candidate_id, id), created_at DESC")
+
+	scored := r.db.Table("evaluation_tasks AS t").
+		Select("t.id AS task_id, t.created_at, "+rankScoreSQL+" AS score, 1 - (e.embedding <=> j.embedding) AS similarity").
+		Joins("JOIN jobs j ON j.id = ?", jobID).
+		Joins("LEFT JOIN documents d ON d.id = t.cv_document_id").
+		// Satu file bisa punya beberapa embedding (per setting ekstraksi); pakai yang terbaru
+		Joins("LEFT JOIN LATERAL (SELECT embedding FROM document_embeddings WHERE hash = d.hash AND model = ? ORDER BY created_at DESC LIMIT 1) e ON true", filter.EmbeddingModel).
+		Where("t.id IN (?)", latest)
+	if filter.VectorPrefilter {
+		scored = scored.Where("e.embedding IS NOT NULL AND j.embedding IS NOT NULL")
+		if filter.MinSimilarity != nil {
+			scored = scored.Where("1 - (e.embedding <=> j.embedding) >= ?", *filter.MinSimilarity)
+		}
+		if filter.TopK > 0 {
+			scored = scored.Order("e.embedding <=> j.embedding").Limit(filter.TopK)
+		}
+	}
+
+	query := r.db.Table("(?) AS ranked", scored)
+	if filter.MinScore != nil {
+		query = query.Where("score >= ?", *filter.MinScore)
+	}
+	// Session supaya Count & Scan memakai statement terpisah
+	query = query.Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var ranked []RankedEvaluation
+	err := query.Select("task_id, score, similarity").
+		Order("score DESC, similarity DESC NULLS LAST, created_at DESC").
+		Offset(offset).Limit(limit).
+		Scan(&ranked).Error
+	return ranked, total, err
+}
+
+// FindTasksByIDs mengambil beberapa task tanpa teks CV & report
+func (r *EvaluationRepository) FindTasksByIDs(ids []uuid.UUID) (map[uuid.UUID]model.EvaluationTask, error) {
+	result := make(map[uuid.UUID]model.EvaluationTask, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+	var tasks []model.EvaluationTask
+	if err := r.db.Omit("cv", "report").Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		result[task.ID] = task
+	}
+	return result, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/google/uuid"
 	"github.com/pgvector/pgvector-go"
 	"gorm.io/gorm"
 )
@@ -36,14 +37,65 @@ func (r *JobRepository) UpdateJob(job *model.Job) error {
 	return r.db.Save(job).Error
 }
 
+// UpdateJobSkills hanya menulis kolom skill, tanpa menyentuh updated_at & embedding
+func (r *JobRepository) UpdateJobSkills(id uuid.UUID, required, niceToHave []string) error {
+	return r.db.Model(&model.Job{ID: id}).
+		Select("required_skills", "nice_to_have_skills").
+		UpdateColumns(&model.Job{RequiredSkills: required, NiceToHaveSkills: niceToHave}).Error
+}
+
 func (r *JobRepository) FindJobByID(id string) (*model.Job, error) {
 	var j model.Job
 	err := r.db.First(&j, "id = ?", id).Error
 	return &j, err
 }
 
+// FindJobsByIDs mengambil beberapa job sekaligus; job yang sudah dihapus dilewati
+func (r *JobRepository) FindJobsByIDs(ids []uuid.UUID) ([]model.Job, error) {
+	var jobs []model.Job
+	if len(ids) == 0 {
+		return jobs, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&jobs).Error
+	return jobs, err
+}
+
+// Fingerprint meringkas versi job (id, updated_at & kolom skill) untuk result
+// key evaluasi: hanya job tersebut kalau id diisi, semua job kalau nil karena
+// konteks RAG bergantung pada seluruh job yang ada. Kolom skill ikut dihitung
+// karena UpdateJobSkills tidak menyentuh updated_at.
+func (r *JobRepository) Fingerprint(id *uuid.UUID) (string, error) {
+	query := r.db.Model(&model.Job{}).
+		Select("md5(COALESCE(string_agg(" +
+			"id::text || '@' || (extract(epoch from updated_at) * 1000000)::bigint::text || '@' || " +
+			"COALESCE(required_skills::text, '') || COALESCE(nice_to_have_skills::text, ''), " +
+			"',' ORDER BY id), ''))")
+	if id != nil {
+		query = query.Where("id = ?", *id)
+	}
+	var fingerprint string
+	err := query.Scan(&fingerprint).Error
+	return fingerprint, err
+}
+
 func (r *JobRepository) GetJobs() ([]model.Job, error) {
 	var jobs []model.Job
 	err := r.db.Find(&jobs).Error
 	return jobs, err
 }
+
+// ListJobs mengambil jobs dengan pagination, diurutkan dari yang terbaru
+func (r *JobRepository) ListJobs(offset, limit int) ([]model.Job, int64, error) {
+	var jobs []model.Job
+	var total int64
+	if err := r.db.Model(&model.Job{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := r.db.Omit("embedding").Order("created_at DESC").Offset(offset).Limit(limit).Find(&jobs).Error
+	return jobs, total, err
+}
+
+func (r *JobRepository) DeleteJob(id string) (int64, error) {
+	res := r.db.Delete(&model.Job{}, "id = ?", id)
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type QueueRepository struct {
+	db *gorm.DB
+}
+
+func NewQueueRepository(db *gorm.DB) *QueueRepository {
+	return &QueueRepository{db}
+}
+
+func (r *QueueRepository) Enqueue(job *model.QueueJob) error {
+	if job.Status == "" {
+		job.Status = model.QueueStatusQueued
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	return r.db.Create(job).Error
+}
+
+// CountPending menghitung job yang belum selesai (queued + processing)
+func (r *QueueRepository) CountPending(kind string) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.QueueJob{}).
+		Where("kind = ? AND status IN ?", kind, []string{model.QueueStatusQueued, model.QueueStatusProcessing}).
+		Count(&count).Error
+	return count, err
+}
+
+// Claim mengambil satu job yang siap dijalankan dan menguncinya dengan lease.
+// FOR UPDATE SKIP LOCKED memastikan beberapa worker tidak mengambil job yang sama.
+func (r *QueueRepository) Claim(kind, workerID string, lease time.Duration) (*model.QueueJob, error) {
+	var jobs []model.QueueJob
+	err := r.db.Raw(`
+        UPDATE queue_jobs
+        SET status = ?, attempts = attempts + 1, locked_by = ?,
+            lease_until = now() + make_interval(secs => ?), updated_at = now()
+        WHERE id = (
+            SELECT id FROM queue_jobs
+            WHERE kind = ? AND status = ? AND run_at <= now()
+            ORDER BY run_at
+            FOR UPDATE SKIP LOCKED
+            LIMIT 1
+        )
+        RETURNING *
+    `, model.QueueStatusProcessing, workerID, lease.Seconds(), kind, model.QueueStatusQueued).Scan(&jobs).Error
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return &jobs[0], nil
+}
+
+// Heartbeat memperpanjang lease job yang sedang diproses.
+// Mengembalikan false kalau job sudah tidak dipegang worker ini lagi.
+func (r *QueueRepository) Heartbeat(id uuid.UUID, workerID string, lease time.Duration) (bool, error) {
+	res := r.db.Exec(`
+        UPDATE queue_jobs
+        SET lease_until = now() + make_interval(secs => ?), updated_at = now()
+        WHERE id = ? AND locked_by = ? AND status = ?
+    `, lease.Seconds(), id, workerID, model.QueueStatusProcessing)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *QueueRepository) Complete(id uuid.UUID) error {
+	return r.db.Model(&model.QueueJob{}).Where("id = ?", id).Updates(map[string]any{
+		"status":      model.QueueStatusDone,
+		"lease_until": nil,
+		"updated_at":  time.Now(),
+	}).Error
+}
+
+// Retry mengembalikan job ke antrian untuk dicoba lagi setelah runAt
+func (r *QueueRepository) Retry(id uuid.UUID, runAt time.Time, lastErr string) error {
+	return r.db.Model(&model.QueueJob{}).Where("id = ?", id).Updates(map[string]any{
+		"status":      model.QueueStatusQueued,
+		"run_at":      runAt,
+		"locked_by":   "",
+		"lease_until": nil,
+		"last_error":  lastErr,
+		"updated_at":  time.Now(),
+	}).Error
+}
+
+// Release mengembalikan job ke antrian tanpa menghitung attempt (mis. saat shutdown)
+func (r *QueueRepository) Release(id uuid.UUID) error {
+	return r.db.Model(&model.QueueJob{}).Where("id = ?", id).Updates(map[string]any{
+		"status":      model.QueueStatusQueued,
+		"attempts":    gorm.Expr("GREATEST(attempts - 1, 0)"),
+		"locked_by":   "",
+		"lease_until": nil,
+		"updated_at":  time.Now(),
+	}).Error
+}
+
+func (r *QueueRepository) Fail(id uuid.UUID, lastErr string) error {
+	return r.db.Model(&model.QueueJob{}).Where("id = ?", id).Updates(map[string]any{
+		"status":      model.QueueStatusFailed,
+		"lease_until": nil,
+		"last_error":  lastErr,
+		"updated_at":  time.Now(),
+	}).Error
+}
+
+// RequeueExpired mengembalikan job "processing" yang lease-nya sudah habis
+// (worker mati/restart) ke antrian.
+func (r *QueueRepository) RequeueExpired() (int64, error) {
+	res := r.db.Exec(`
+        UPDATE queue_jobs
+        SET status = ?, locked_by = '', lease_until = NULL, run_at = now(), updated_at = now()
+        WHERE status = ? AND lease_until < now()
+    `, model.QueueStatusQueued, model.QueueStatusProcessing)
+	return res.RowsAffected, res.Error
+}
+
+// EnqueueOrphanedTasks membuat job untuk task yang belum selesai tapi belum punya job
+// sama sekali, mis. task lama sebelum antrian ada atau enqueue yang gagal.
+func (r *QueueRepository) EnqueueOrphanedTasks(kind string, maxAttempts int) (int64, error) {
+	res := r.db.Exec(`
+        INSERT INTO queue_jobs (task_id, kind, status, attempts, max_attempts, run_at, created_at, updated_at)
+        SELECT t.id, ?, ?, 0, ?, now(), now(), now()
+        FROM evaluation_tasks t
+        WHERE t.status NOT IN (?, ?)
+          AND NOT EXISTS (SELECT 1 FROM queue_jobs q WHERE q.task_id = t.id AND q.kind = ?)
+    `, kind, model.QueueStatusQueued, maxAttempts, model.TaskStatusCompleted, model.TaskStatusFailed, kind)
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RubricRepository struct {
+	db *gorm.DB
+}
+
+func NewRubricRepository(db *gorm.DB) *RubricRepository {
+	return &RubricRepository{db}
+}
+
+// CreateActive menyimpan rubric sebagai versi aktif dan menonaktifkan versi lain
+// di group yang sama serta rubric aktif lain untuk job yang sama
+func (r *RubricRepository) CreateActive(rubric *model.Rubric) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		deactivate := tx.Model(&model.Rubric{}).Where("active = ?", true)
+		if rubric.JobID != nil {
+			deactivate = deactivate.Where("group_id = ? OR job_id = ?", rubric.GroupID, *rubric.JobID)
+		} else {
+			deactivate = deactivate.Where("group_id = ? OR job_id IS NULL", rubric.GroupID)
+		}
+		if err := deactivate.Update("active", false).Error; err != nil {
+			return err
+		}
+
+		rubric.Active = true
+		return tx.Create(rubric).Error
+	})
+}
+
+func (r *RubricRepository) FindByID(id string) (*model.Rubric, error) {
+	var rubric model.Rubric
+	err := r.db.First(&rubric, "id = ?", id).Error
+	return &rubric, err
+}
+
+// FindActive mengambil rubric aktif untuk job, atau rubric default kalau jobID nil
+func (r *RubricRepository) FindActive(jobID *uuid.UUID) (*model.Rubric, error) {
+	var rubric model.Rubric
+	query := r.db.Where("active = ?", true)
+	if jobID != nil {
+		query = query.Where("job_id = ?", *jobID)
+	} else {
+		query = query.Where("job_id IS NULL")
+	}
+	err := query.Order("version DESC").First(&rubric).Error
+	return &rubric, err
+}
+
+func (r *RubricRepository) NextVersion(groupID uuid.UUID) (int, error) {
+	var version int
+	err := r.db.Model(&model.Rubric{}).Where("group_id = ?", groupID).
+		Select("COALESCE(MAX(version), 0) + 1").Scan(&version).Error
+	return version, err
+}
+
+func (r *RubricRepository) List(jobID *uuid.UUID, activeOnly bool, offset, limit int) ([]model.Rubric, int64, error) {
+	var rubrics []model.Rubric
+	var total int64
+
+	query := r.db.Model(&model.Rubric{})
+	if jobID != nil {
+		query = query.Where("job_id = ?", *jobID)
+	}
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&rubrics).Error
+	return rubrics, total, err
+}
+
+func (r *RubricRepository) Deactivate(id string) (int64, error) {
+	res := r.db.Model(&model.Rubric{}).Where("id = ? AND active = ?", id, true).Update("active", false)
+	return res.RowsAffected, res.Error
+}
+
+func (r *RubricRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&model.Rubric{}).Count(&count).Error
+	return count, err
+}
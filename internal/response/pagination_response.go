@@ -1,5 +1,7 @@
 package response
 
+import "math"
+
 type Pagination struct {
 	Page       int   `json:"page"`
 	PageSize   int   `json:"page_size"`
@@ -9,3 +11,22 @@ type Pagination struct {
 	From       int   `json:"from"`
 	To         int   `json:"to"`
 }
+
+// NewPagination menyusun Pagination dari page (1-based), ukuran halaman,
+// jumlah item di halaman ini dan total item
+func NewPagination(page, pageSize, count int, total int64) *Pagination {
+	from, to := 0, 0
+	if count > 0 {
+		from = (page-1)*pageSize + 1
+		to = from + count - 1
+	}
+	return &Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int64(math.Ceil(float64(total) / float64(pageSize))),
+		TotalItems: total,
+		HasMore:    int64(page*pageSize) < total,
+		From:       from,
+		To:         to,
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// FailoverProvider meneruskan request ke provider berikutnya kalau provider
+// sebelumnya gagal (termasuk saat circuit breaker-nya open).
+type FailoverProvider struct {
+	providers []LLMProvider
+}
+
+func NewFailoverProvider(providers ...LLMProvider) *FailoverProvider {
+	return &FailoverProvider{providers: providers}
+}
+
+func (p *FailoverProvider) Name() string {
+	names := make([]string, 0, len(p.providers))
+	for _, provider := range p.providers {
+		names = append(names, provider.Name())
+	}
+	return strings.Join(names, ">")
+}
+
+func (p *FailoverProvider) ModelID() string {
+	ids := make([]string, 0, len(p.providers))
+	for _, provider := range p.providers {
+		ids = append(ids, provider.ModelID())
+	}
+	return strings.Join(ids, ">")
+}
+
+// GenerateText mengembalikan hasil provider pertama yang berhasil; LLMResponse.Provider
+// berisi provider yang benar-benar menghasilkan jawaban
+func (p *FailoverProvider) GenerateText(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	var errs []error
+	for i, provider := range p.providers {
+		// Model override hanya berlaku untuk provider utama
+		attempt := req
+		if i > 0 {
+			attempt.Model = ""
+		}
+
+		result, err := provider.GenerateText(ctx, attempt)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+		if i < len(p.providers)-1 {
+			log.Printf("LLM provider %s failed, failing over to %s: %v", provider.Name(), p.providers[i+1].Name(), err)
+		}
+	}
+	return nil, fmt.Errorf("all LLM providers failed: %w", errors.Join(errs...))
+}
+
+// GenerateEmbedding selalu memakai provider utama: embedding dari model
+// berbeda tidak bisa dibandingkan dengan vector yang sudah tersimpan
+func (p *FailoverProvider) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	return p.providers[0].GenerateEmbedding(ctx, text)
+}
+
+func (p *FailoverProvider) EmbeddingModelID() string {
+	return p.providers[0].EmbeddingModelID()
+}
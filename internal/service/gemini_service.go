@@ -2,31 +2,28 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"strings"
 	"time"
 
+	"github.com/fadilmartias/cv-analyzer/internal/circuitbreaker"
 	"github.com/fadilmartias/cv-analyzer/internal/config"
 	"google.golang.org/genai"
 )
 
-type GeminiServiceInterface interface {
-	GenerateEmbedding(ctx context.Context, text string) ([]float32, error)
-	GenerateContent(ctx context.Context, model string, prompt string) (*genai.GenerateContentResponse, error)
-	Test() (string, error)
-}
-
 type GeminiService struct {
-	Client            *genai.Client
-	Ctx               context.Context
-	MaxRetries        int
-	BaseDelay         time.Duration
-	MaxDelay          time.Duration
-	RequestTimeout    time.Duration
-	consecutiveErrors int
-	circuitBreakerMax int
+	Client         *genai.Client
+	Ctx            context.Context
+	Model          string
+	EmbeddingModel string
+	MaxRetries     int
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	RequestTimeout time.Duration
+	breaker        *circuitbreaker.Breaker
 }
 
 func NewGeminiService(ctx context.Context) (*GeminiService, error) {
@@ -44,30 +41,64 @@ func NewGeminiService(ctx context.Context) (*GeminiService, error) {
 		log.Fatal(err)
 	}
 	return &GeminiService{
-		Client:            client,
-		Ctx:               ctx,
-		MaxRetries:        3,
-		BaseDelay:         time.Second,
-		MaxDelay:          90 * time.Second,
-		RequestTimeout:    90 * time.Second,
-		circuitBreakerMax: 5,
+		Client:         client,
+		Ctx:            ctx,
+		Model:          geminiConfig.Model,
+		EmbeddingModel: geminiConfig.EmbeddingModel,
+		MaxRetries:     3,
+		BaseDelay:      time.Second,
+		MaxDelay:       90 * time.Second,
+		RequestTimeout: 90 * time.Second,
+		breaker:        newCircuitBreaker(ProviderGemini),
 	}, nil
 }
 
-func (s *GeminiService) Test() (string, error) {
-	result, err := s.Client.Models.GenerateContent(
-		s.Ctx,
-		"gemini-2.5-flash",
-		genai.Text("Explain how AI works in a few words"),
-		nil,
-	)
+func (s *GeminiService) Name() string {
+	return ProviderGemini
+}
+
+func (s *GeminiService) ModelID() string {
+	return s.Name() + "/" + s.Model
+}
+
+func (s *GeminiService) EmbeddingModelID() string {
+	return s.Name() + "/" + s.EmbeddingModel
+}
+
+// GenerateText implementasi LLMProvider; JSON/Schema dipetakan ke response MIME type & JSON schema Gemini
+func (s *GeminiService) GenerateText(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = s.Model
+	}
+
+	genConfig := &genai.GenerateContentConfig{
+		Temperature: genai.Ptr(req.Temperature),
+	}
+	if req.System != "" {
+		genConfig.SystemInstruction = genai.NewContentFromText(req.System, genai.RoleUser)
+	}
+	if req.JSON || req.Schema != nil {
+		genConfig.ResponseMIMEType = "application/json"
+	}
+	if req.Schema != nil {
+		genConfig.ResponseJsonSchema = req.Schema
+	}
+
+	result, err := s.generateContent(ctx, model, req.Prompt, genConfig)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return result.Text(), nil
+	return &LLMResponse{Text: result.Text(), Provider: s.Name(), Model: model}, nil
 }
 
 func (s *GeminiService) GenerateContent(ctx context.Context, model string, prompt string) (*genai.GenerateContentResponse, error) {
+	return s.generateContent(ctx, model, prompt, &genai.GenerateContentConfig{
+		Temperature: genai.Ptr(float32(0.1)),
+	})
+}
+
+func (s *GeminiService) generateContent(ctx context.Context, model string, prompt string, genConfig *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
 	if model == "" {
 		return nil, fmt.Errorf("model name cannot be empty")
 	}
@@ -75,8 +106,8 @@ func (s *GeminiService) GenerateContent(ctx context.Context, model string, promp
 		return nil, fmt.Errorf("prompt cannot be empty")
 	}
 
-	if s.consecutiveErrors >= s.circuitBreakerMax {
-		return nil, fmt.Errorf("circuit breaker open: too many consecutive errors (%d)", s.consecutiveErrors)
+	if err := s.breaker.Allow(); err != nil {
+		return nil, err
 	}
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, s.RequestTimeout)
@@ -92,14 +123,11 @@ func (s *GeminiService) GenerateContent(ctx context.Context, model string, promp
 			select {
 			case <-time.After(delay):
 			case <-timeoutCtx.Done():
+				s.failure(ctx)
 				return nil, fmt.Errorf("context timeout during retry: %w", timeoutCtx.Err())
 			}
 		}
 
-		genConfig := &genai.GenerateContentConfig{
-			Temperature: genai.Ptr(float32(0.1)),
-		}
-
 		result, err := s.Client.Models.GenerateContent(
 			timeoutCtx,
 			model,
@@ -108,11 +136,12 @@ func (s *GeminiService) GenerateContent(ctx context.Context, model string, promp
 		)
 
 		if err == nil {
-			s.consecutiveErrors = 0
+			// Respons kosong/diblokir dihitung gagal supaya tidak menutup breaker half-open
 			if err := s.validateGenerateResponse(result); err != nil {
+				s.breaker.Failure()
 				return nil, fmt.Errorf("invalid response: %w", err)
 			}
-
+			s.breaker.Success()
 			return result, nil
 		}
 
@@ -120,14 +149,14 @@ func (s *GeminiService) GenerateContent(ctx context.Context, model string, promp
 
 		if !s.isRetryableError(err) {
 			log.Printf("Non-retryable error: %v", err)
-			s.consecutiveErrors++
+			s.failure(ctx)
 			return nil, fmt.Errorf("generate content failed: %w", err)
 		}
 
 		log.Printf("Retryable error on attempt %d: %v", attempt+1, err)
 	}
 
-	s.consecutiveErrors++
+	s.failure(ctx)
 	return nil, fmt.Errorf("max retries (%d) exceeded for GenerateContent: %w", s.MaxRetries, lastErr)
 }
 
@@ -142,8 +171,8 @@ func (s *GeminiService) GenerateEmbedding(ctx context.Context, text string) ([]f
 		trimmedText = trimmedText[:10000]
 	}
 
-	if s.consecutiveErrors >= s.circuitBreakerMax {
-		return nil, fmt.Errorf("circuit breaker open: too many consecutive errors (%d)", s.consecutiveErrors)
+	if err := s.breaker.Allow(); err != nil {
+		return nil, err
 	}
 	timeoutCtx, cancel := context.WithTimeout(ctx, s.RequestTimeout)
 	defer cancel()
@@ -161,24 +190,25 @@ func (s *GeminiService) GenerateEmbedding(ctx context.Context, text string) ([]f
 			case <-time.After(delay):
 				// Continue to retry
 			case <-timeoutCtx.Done():
+				s.failure(ctx)
 				return nil, fmt.Errorf("context timeout during retry: %w", timeoutCtx.Err())
 			}
 		}
 
 		result, err := s.Client.Models.EmbedContent(
 			timeoutCtx,
-			"gemini-embedding-001",
+			s.EmbeddingModel,
 			content,
 			nil,
 		)
 
 		if err == nil {
-			s.consecutiveErrors = 0
 			embeddings, err := s.validateEmbeddingResponse(result)
 			if err != nil {
+				s.breaker.Failure()
 				return nil, fmt.Errorf("invalid embedding response: %w", err)
 			}
-
+			s.breaker.Success()
 			return embeddings, nil
 		}
 
@@ -186,16 +216,28 @@ func (s *GeminiService) GenerateEmbedding(ctx context.Context, text string) ([]f
 
 		if !s.isRetryableError(err) {
 			log.Printf("Non-retryable error: %v", err)
-			s.consecutiveErrors++
+			s.failure(ctx)
 			return nil, fmt.Errorf("generate embedding failed: %w", err)
 		}
 
 		log.Printf("Retryable error on attempt %d: %v", attempt+1, err)
 	}
 
-	s.consecutiveErrors++
+	s.failure(ctx)
 	return nil, fmt.Errorf("max retries (%d) exceeded for GenerateEmbedding: %w", s.MaxRetries, lastErr)
 }
+
+// failure melaporkan request yang gagal ke circuit breaker; pembatalan dari
+// pemanggil (worker shutdown, lease hilang) tidak dihitung sebagai kegagalan
+// provider, sama seperti OpenAICompatibleService.report
+func (s *GeminiService) failure(ctx context.Context) {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		s.breaker.Release()
+		return
+	}
+	s.breaker.Failure()
+}
+
 func (s *GeminiService) calculateBackoff(attempt int) time.Duration {
 	delay := s.BaseDelay * time.Duration(math.Pow(2, float64(attempt-1)))
 
@@ -287,9 +329,9 @@ func (s *GeminiService) validateEmbeddingResponse(resp *genai.EmbedContentRespon
 }
 
 func (s *GeminiService) ResetCircuitBreaker() {
-	s.consecutiveErrors = 0
-	log.Println("Circuit breaker reset")
+	s.breaker.Reset()
 }
-func (s *GeminiService) GetCircuitBreakerStatus() (consecutiveErrors int, isOpen bool) {
-	return s.consecutiveErrors, s.consecutiveErrors >= s.circuitBreakerMax
+
+func (s *GeminiService) GetCircuitBreakerStatus() circuitbreaker.Snapshot {
+	return s.breaker.Snapshot()
 }
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fadilmartias/cv-analyzer/internal/circuitbreaker"
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+)
+
+// EmbeddingDimensions harus sama dengan dimensi kolom jobs.embedding (vector(3072))
+const EmbeddingDimensions = 3072
+
+const (
+	ProviderGemini     = "gemini"
+	ProviderOpenRouter = "openrouter"
+	ProviderLocal      = "local"
+)
+
+type Embedder interface {
+	GenerateEmbedding(ctx context.Context, text string) ([]float32, error)
+	// EmbeddingModelID mengidentifikasi provider & model embedding ("gemini/gemini-embedding-001"),
+	// dipakai sebagai bagian key cache embedding
+	EmbeddingModelID() string
+}
+
+// LLMProvider adalah abstraksi tunggal untuk semua backend LLM
+// (chat completion, embeddings dan structured output)
+type LLMProvider interface {
+	Embedder
+	Name() string
+	// ModelID mengidentifikasi provider & model default untuk GenerateText
+	ModelID() string
+	GenerateText(ctx context.Context, req LLMRequest) (*LLMResponse, error)
+}
+
+type LLMRequest struct {
+	Model       string // kosong = model default provider
+	System      string
+	Prompt      string
+	Temperature float32
+	// JSON meminta output berupa JSON; kalau Schema diisi, output harus sesuai JSON Schema tersebut
+	JSON   bool
+	Schema map[string]any
+}
+
+type LLMResponse struct {
+	Text     string
+	Provider string
+	Model    string
+}
+
+// NewLLMProvider membuat provider berdasarkan nama di config (LLM_PROVIDER / EMBEDDING_PROVIDER)
+func NewLLMProvider(ctx context.Context, name string) (LLMProvider, error) {
+	switch name {
+	case ProviderGemini:
+		gemini, err := NewGeminiService(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return gemini, nil
+	case ProviderOpenRouter:
+		openRouter, err := NewOpenRouterService()
+		if err != nil {
+			return nil, err
+		}
+		return openRouter, nil
+	case ProviderLocal:
+		return NewLocalLLMService(), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", name)
+	}
+}
+
+// NewLLMProviders membuat provider untuk generate (dengan failover ke
+// LLM_FALLBACK_PROVIDERS kalau diisi) dan embedding. Provider dengan nama sama
+// dipakai bersama supaya state circuit breaker-nya juga sama.
+func NewLLMProviders(ctx context.Context, cfg *config.LLMConfig) (LLMProvider, LLMProvider, error) {
+	instances := map[string]LLMProvider{}
+	get := func(name string) (LLMProvider, error) {
+		if provider, ok := instances[name]; ok {
+			return provider, nil
+		}
+		provider, err := NewLLMProvider(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("init %s provider: %w", name, err)
+		}
+		instances[name] = provider
+		return provider, nil
+	}
+
+	primary, err := get(cfg.Provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	llm := primary
+	if len(cfg.FallbackProviders) > 0 {
+		chain := []LLMProvider{primary}
+		for _, name := range cfg.FallbackProviders {
+			fallback, err := get(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			chain = append(chain, fallback)
+		}
+		llm = NewFailoverProvider(chain...)
+	}
+
+	embedder, err := get(cfg.EmbeddingProvider)
+	if err != nil {
+		return nil, nil, err
+	}
+	return llm, embedder, nil
+}
+
+// fitEmbeddingDimensions melakukan zero-padding embedding yang lebih pendek dari
+// EmbeddingDimensions. Padding nol tidak mengubah jarak L2 maupun cosine, jadi
+// model embedding lokal tetap bisa dipakai dengan kolom vector(3072).
+func fitEmbeddingDimensions(embedding []float32) ([]float32, error) {
+	if len(embedding) > EmbeddingDimensions {
+		return nil, fmt.Errorf("embedding has %d dimensions, max %d", len(embedding), EmbeddingDimensions)
+	}
+	if len(embedding) == EmbeddingDimensions {
+		return embedding, nil
+	}
+	padded := make([]float32, EmbeddingDimensions)
+	copy(padded, embedding)
+	return padded, nil
+}
+
+// newCircuitBreaker membuat breaker untuk client keluar dengan setting dari config
+func newCircuitBreaker(name string) *circuitbreaker.Breaker {
+	cfg := config.LoadCircuitBreakerConfig()
+	return circuitbreaker.New(name, circuitbreaker.Config{
+		Window:      cfg.Window,
+		MinRequests: cfg.MinRequests,
+		FailureRate: cfg.FailureRate,
+		Cooldown:    cfg.Cooldown,
+		MaxProbes:   cfg.MaxProbes,
+	})
+}
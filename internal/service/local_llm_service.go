@@ -0,0 +1,12 @@
+package service
+
+import (
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+)
+
+// NewLocalLLMService membuat provider untuk endpoint OpenAI-compatible lokal
+// (mis. Ollama atau llama.cpp server), supaya stack bisa jalan offline
+func NewLocalLLMService() *OpenAICompatibleService {
+	cfg := config.LoadLocalLLMConfig()
+	return NewOpenAICompatibleService(ProviderLocal, cfg.BaseURL, cfg.APIKey, cfg.Model, cfg.EmbeddingModel)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/circuitbreaker"
+	"github.com/go-resty/resty/v2"
+)
+
+// OpenAICompatibleService adalah client untuk API yang kompatibel dengan OpenAI
+// (/chat/completions & /embeddings): OpenRouter, Ollama, llama.cpp server, dsb.
+type OpenAICompatibleService struct {
+	name           string
+	client         *resty.Client
+	breaker        *circuitbreaker.Breaker
+	Model          string
+	EmbeddingModel string
+}
+
+func NewOpenAICompatibleService(name, baseURL, apiKey, model, embeddingModel string) *OpenAICompatibleService {
+	client := resty.New().
+		SetBaseURL(strings.TrimRight(baseURL, "/")).
+		SetHeader("Content-Type", "application/json").
+		SetTimeout(90 * time.Second).
+		SetRetryCount(3).
+		SetRetryWaitTime(time.Second).
+		SetRetryMaxWaitTime(30 * time.Second).
+		AddRetryCondition(func(r *resty.Response, err error) bool {
+			if err != nil {
+				return !strings.Contains(err.Error(), "context canceled")
+			}
+			code := r.StatusCode()
+			return code == 429 || code >= 500
+		})
+	if apiKey != "" {
+		client.SetAuthToken(apiKey)
+	}
+
+	return &OpenAICompatibleService{
+		name:           name,
+		client:         client,
+		breaker:        newCircuitBreaker(name),
+		Model:          model,
+		EmbeddingModel: embeddingModel,
+	}
+}
+
+func (s *OpenAICompatibleService) Name() string {
+	return s.name
+}
+
+func (s *OpenAICompatibleService) ModelID() string {
+	return s.name + "/" + s.Model
+}
+
+func (s *OpenAICompatibleService) EmbeddingModelID() string {
+	return s.name + "/" + s.EmbeddingModel
+}
+
+type chatCompletionResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+}
+
+func (s *OpenAICompatibleService) GenerateText(ctx context.Context, req LLMRequest) (*LLMResponse, error) {
+	if strings.TrimSpace(req.Prompt) == "" {
+		return nil, fmt.Errorf("prompt cannot be empty")
+	}
+	model := req.Model
+	if model == "" {
+		model = s.Model
+	}
+
+	messages := []map[string]string{}
+	if req.System != "" {
+		messages = append(messages, map[string]string{"role": "system", "content": req.System})
+	}
+	messages = append(messages, map[string]string{"role": "user", "content": req.Prompt})
+
+	body := map[string]any{
+		"model":       model,
+		"messages":    messages,
+		"temperature": req.Temperature,
+	}
+	switch {
+	case req.Schema != nil:
+		body["response_format"] = map[string]any{
+			"type": "json_schema",
+			"json_schema": map[string]any{
+				"name":   "response",
+				"strict": true,
+				"schema": req.Schema,
+			},
+		}
+	case req.JSON:
+		body["response_format"] = map[string]any{"type": "json_object"}
+	}
+
+	if err := s.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	result, err := s.chatCompletion(ctx, model, body)
+	s.report(ctx, err)
+	return result, err
+}
+
+func (s *OpenAICompatibleService) chatCompletion(ctx context.Context, model string, body map[string]any) (*LLMResponse, error) {
+	var parsed chatCompletionResponse
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetResult(&parsed).
+		Post("/chat/completions")
+	if err != nil {
+		return nil, fmt.Errorf("%s chat completion failed: %w", s.name, err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("%s chat completion failed: status %d: %s", s.name, resp.StatusCode(), resp.String())
+	}
+	if len(parsed.Choices) == 0 || strings.TrimSpace(parsed.Choices[0].Message.Content) == "" {
+		return nil, fmt.Errorf("%s returned no content", s.name)
+	}
+
+	if parsed.Model != "" {
+		model = parsed.Model
+	}
+	return &LLMResponse{Text: parsed.Choices[0].Message.Content, Provider: s.name, Model: model}, nil
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+func (s *OpenAICompatibleService) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	if s.EmbeddingModel == "" {
+		return nil, fmt.Errorf("%s: embedding model not configured", s.name)
+	}
+	trimmedText := strings.TrimSpace(text)
+	if trimmedText == "" {
+		return nil, fmt.Errorf("text for embedding cannot be empty")
+	}
+	if len(trimmedText) > 10000 {
+		log.Printf("Warning: text length %d exceeds recommended limit, truncating...", len(trimmedText))
+		trimmedText = trimmedText[:10000]
+	}
+
+	if err := s.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	embedding, err := s.embedding(ctx, trimmedText)
+	s.report(ctx, err)
+	return embedding, err
+}
+
+func (s *OpenAICompatibleService) embedding(ctx context.Context, trimmedText string) ([]float32, error) {
+	var parsed embeddingResponse
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetBody(map[string]any{
+			"model": s.EmbeddingModel,
+			"input": trimmedText,
+		}).
+		SetResult(&parsed).
+		Post("/embeddings")
+	if err != nil {
+		return nil, fmt.Errorf("%s embedding failed: %w", s.name, err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("%s embedding failed: status %d: %s", s.name, resp.StatusCode(), resp.String())
+	}
+	if len(parsed.Data) == 0 || len(parsed.Data[0].Embedding) == 0 {
+		return nil, fmt.Errorf("%s returned no embeddings", s.name)
+	}
+
+	embedding := parsed.Data[0].Embedding
+	for i, val := range embedding {
+		if math.IsNaN(float64(val)) || math.IsInf(float64(val), 0) {
+			return nil, fmt.Errorf("invalid embedding value at index %d: %v", i, val)
+		}
+	}
+	return fitEmbeddingDimensions(embedding)
+}
+
+// report melaporkan hasil request ke circuit breaker; pembatalan dari
+// pemanggil tidak dihitung sebagai kegagalan provider
+func (s *OpenAICompatibleService) report(ctx context.Context, err error) {
+	switch {
+	case err == nil:
+		s.breaker.Success()
+	case errors.Is(ctx.Err(), context.Canceled):
+		s.breaker.Release()
+	default:
+		s.breaker.Failure()
+	}
+}
@@ -0,0 +1,59 @@
+package skills
+
+import (
+	"math"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+)
+
+// CandidateSkills menggabungkan skill dari profil CV (dinormalisasi) dengan
+// skill taxonomy yang disebut di teks CV, misalnya di deskripsi pengalaman
+func (t *Taxonomy) CandidateSkills(profileSkills []string, cv string) []string {
+	return t.NormalizeAll(append(append([]string{}, profileSkills...), t.Find(cv)...))
+}
+
+// Gap membandingkan skill kandidat dengan skill wajib & nice-to-have satu job
+func Gap(candidate, required, niceToHave []string) model.JobSkillGap {
+	has := toSet(candidate)
+	gap := model.JobSkillGap{
+		Matched:           []string{},
+		Missing:           []string{},
+		MatchedNiceToHave: []string{},
+		MissingNiceToHave: []string{},
+		Extra:             []string{},
+		Coverage:          1,
+	}
+	for _, s := range required {
+		if has[strings.ToLower(s)] {
+			gap.Matched = append(gap.Matched, s)
+		} else {
+			gap.Missing = append(gap.Missing, s)
+		}
+	}
+	for _, s := range niceToHave {
+		if has[strings.ToLower(s)] {
+			gap.MatchedNiceToHave = append(gap.MatchedNiceToHave, s)
+		} else {
+			gap.MissingNiceToHave = append(gap.MissingNiceToHave, s)
+		}
+	}
+	mentioned := toSet(append(append([]string{}, required...), niceToHave...))
+	for _, s := range candidate {
+		if !mentioned[strings.ToLower(s)] {
+			gap.Extra = append(gap.Extra, s)
+		}
+	}
+	if len(required) > 0 {
+		gap.Coverage = math.Round(float64(len(gap.Matched))/float64(len(required))*100) / 100
+	}
+	return gap
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToLower(v)] = true
+	}
+	return set
+}
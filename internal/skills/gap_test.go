@@ -0,0 +1,92 @@
+package skills
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+)
+
+func TestGap(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidate  []string
+		required   []string
+		niceToHave []string
+		want       model.JobSkillGap
+	}{
+		{
+			name:       "matched, missing and extra",
+			candidate:  []string{"Go", "Docker", "Python"},
+			required:   []string{"Go", "PostgreSQL"},
+			niceToHave: []string{"Docker", "Redis"},
+			want: model.JobSkillGap{
+				Matched:           []string{"Go"},
+				Missing:           []string{"PostgreSQL"},
+				MatchedNiceToHave: []string{"Docker"},
+				MissingNiceToHave: []string{"Redis"},
+				Extra:             []string{"Python"},
+				Coverage:          0.5,
+			},
+		},
+		{
+			name:      "case-insensitive",
+			candidate: []string{"go", "react native"},
+			required:  []string{"Go", "React Native"},
+			want: model.JobSkillGap{
+				Matched:           []string{"Go", "React Native"},
+				Missing:           []string{},
+				MatchedNiceToHave: []string{},
+				MissingNiceToHave: []string{},
+				Extra:             []string{},
+				Coverage:          1,
+			},
+		},
+		{
+			name:      "react does not cover react native",
+			candidate: []string{"React"},
+			required:  []string{"React Native", "TypeScript", "Redux"},
+			want: model.JobSkillGap{
+				Matched:           []string{},
+				Missing:           []string{"React Native", "TypeScript", "Redux"},
+				MatchedNiceToHave: []string{},
+				MissingNiceToHave: []string{},
+				Extra:             []string{"React"},
+				Coverage:          0,
+			},
+		},
+		{
+			name:      "coverage rounded to two decimals",
+			candidate: []string{"Go"},
+			required:  []string{"Go", "Docker", "Kafka"},
+			want: model.JobSkillGap{
+				Matched:           []string{"Go"},
+				Missing:           []string{"Docker", "Kafka"},
+				MatchedNiceToHave: []string{},
+				MissingNiceToHave: []string{},
+				Extra:             []string{},
+				Coverage:          0.33,
+			},
+		},
+		{
+			name:       "no required skills",
+			candidate:  []string{"Go"},
+			niceToHave: []string{"Go"},
+			want: model.JobSkillGap{
+				Matched:           []string{},
+				Missing:           []string{},
+				MatchedNiceToHave: []string{"Go"},
+				MissingNiceToHave: []string{},
+				Extra:             []string{},
+				Coverage:          1,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Gap(tt.candidate, tt.required, tt.niceToHave); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Gap() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
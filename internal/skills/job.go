@@ -0,0 +1,59 @@
+package skills
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// niceToHavePattern menandai bagian/kalimat berisi skill tambahan (bukan wajib)
+	niceToHavePattern = regexp.MustCompile(`(?i)nice[- ]to[- ]have|good[- ]to[- ]have|preferred|bonus|\bplus\b|is an advantage|advantageous|optional|nilai tambah|nilai plus|diutamakan|lebih disukai|menjadi keunggulan`)
+	// headingPattern mengenali baris judul bagian, mis. "Requirements:" atau "## Kualifikasi"
+	headingPattern = regexp.MustCompile(`^(#+\s*.+|[^.!?]{2,60}:)$`)
+	// clauseSeparator memecah satu baris jadi kalimat, supaya "Go; Docker is a plus"
+	// hanya menjadikan Docker nice-to-have
+	clauseSeparator = regexp.MustCompile(`[.;]\s+`)
+)
+
+// JobSkills memisahkan skill di deskripsi job menjadi wajib & nice-to-have.
+// Skill di bawah judul seperti "Nice to have:" / "Nilai tambah:" atau di
+// kalimat yang menyebut "is a plus" / "diutamakan" dianggap nice-to-have; skill
+// lain dianggap wajib. Skill yang muncul di keduanya tetap wajib.
+func (t *Taxonomy) JobSkills(content string) (required, niceToHave []string) {
+	var requiredText, niceText strings.Builder
+	inNiceSection := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if headingPattern.MatchString(trimmed) {
+			inNiceSection = niceToHavePattern.MatchString(trimmed)
+		}
+		if inNiceSection {
+			niceText.WriteString(trimmed + "\n")
+			continue
+		}
+		// Kalimat seperti "Bonus: Docker, Kubernetes" atau "Kafka is a plus"
+		for _, clause := range clauseSeparator.Split(trimmed, -1) {
+			if niceToHavePattern.MatchString(clause) {
+				niceText.WriteString(clause + "\n")
+			} else {
+				requiredText.WriteString(clause + "\n")
+			}
+		}
+	}
+
+	required = t.Find(requiredText.String())
+	isRequired := map[string]bool{}
+	for _, s := range required {
+		isRequired[s] = true
+	}
+	niceToHave = []string{}
+	for _, s := range t.Find(niceText.String()) {
+		if !isRequired[s] {
+			niceToHave = append(niceToHave, s)
+		}
+	}
+	return required, niceToHave
+}
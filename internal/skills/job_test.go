@@ -0,0 +1,70 @@
+package skills
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestJobSkills(t *testing.T) {
+	tax := defaultTaxonomyForTest(t)
+	tests := []struct {
+		name           string
+		content        string
+		wantRequired   []string
+		wantNiceToHave []string
+	}{
+		{
+			name:           "nice-to-have heading",
+			content:        "Requirements:\n- Golang\n- PostgreSQL\n\nNice to have:\n- Docker\n- Kubernetes",
+			wantRequired:   []string{"Go", "PostgreSQL"},
+			wantNiceToHave: []string{"Docker", "Kubernetes"},
+		},
+		{
+			name:           "markdown heading in Indonesian",
+			content:        "## Kualifikasi\n- Menguasai Express.js\n\n## Nilai Tambah\n- Redis",
+			wantRequired:   []string{"Express"},
+			wantNiceToHave: []string{"Redis"},
+		},
+		{
+			name:           "next heading ends the nice-to-have section",
+			content:        "Nice to have:\n- Redis\nResponsibilities:\n- Build APIs with Go",
+			wantRequired:   []string{"Go"},
+			wantNiceToHave: []string{"Redis"},
+		},
+		{
+			name:           "inline clause",
+			content:        "Experience with Go; Docker is a plus. Kafka diutamakan",
+			wantRequired:   []string{"Go"},
+			wantNiceToHave: []string{"Docker", "Kafka"},
+		},
+		{
+			name:           "skill in both stays required",
+			content:        "Must know Go and Docker.\nNice to have:\n- Docker, Redis",
+			wantRequired:   []string{"Go", "Docker"},
+			wantNiceToHave: []string{"Redis"},
+		},
+		{
+			name:           "react native vs react",
+			content:        "Requirements:\n- React Native\nBonus: React",
+			wantRequired:   []string{"React Native"},
+			wantNiceToHave: []string{"React"},
+		},
+		{
+			name:           "no skills",
+			content:        "We are a friendly team.",
+			wantRequired:   []string{},
+			wantNiceToHave: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			required, niceToHave := tax.JobSkills(tt.content)
+			if !slices.Equal(required, tt.wantRequired) {
+				t.Errorf("required = %v, want %v", required, tt.wantRequired)
+			}
+			if !slices.Equal(niceToHave, tt.wantNiceToHave) {
+				t.Errorf("nice-to-have = %v, want %v", niceToHave, tt.wantNiceToHave)
+			}
+		})
+	}
+}
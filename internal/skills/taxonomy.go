@@ -0,0 +1,232 @@
+package skills
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed default_taxonomy.json
+var defaultTaxonomy []byte
+
+// Skill adalah satu skill kanonik beserta alias-aliasnya, mis. PostgreSQL
+// dengan alias "postgres" & "psql"
+type Skill struct {
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Aliases  []string `json:"aliases"`
+	// CaseSensitive dipakai untuk nama yang juga kata biasa (mis. "Go"): saat
+	// mencari di teks bebas, nama kanonik harus cocok persis huruf besar-kecilnya.
+	// Alias tetap case-insensitive, jadi "Golang" & "golang" sama-sama terbaca Go.
+	CaseSensitive bool `json:"case_sensitive"`
+}
+
+// Taxonomy menormalisasi nama skill ke nama kanonik dan mencari skill di teks
+type Taxonomy struct {
+	skills []Skill
+	// index memetakan alias yang sudah di-normalize ke nama kanonik
+	index map[string]string
+	terms []term
+	// checksum adalah hash isi file taxonomy, bagian dari result key evaluasi
+	checksum string
+}
+
+// term adalah satu nama/alias yang dicari di teks bebas
+type term struct {
+	text          string
+	canonical     string
+	caseSensitive bool
+}
+
+// Load membaca taxonomy dari file JSON; path kosong = taxonomy bawaan
+func Load(path string) (*Taxonomy, error) {
+	data := defaultTaxonomy
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("read skills taxonomy: %w", err)
+		}
+	}
+	var list []Skill
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parse skills taxonomy %s: %w", path, err)
+	}
+	t, err := New(list)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	t.checksum = hex.EncodeToString(sum[:8])
+	return t, nil
+}
+
+// New membangun taxonomy; alias yang dipakai oleh dua skill berbeda ditolak
+func New(list []Skill) (*Taxonomy, error) {
+	t := &Taxonomy{skills: list, index: map[string]string{}}
+	for _, s := range list {
+		s.Name = strings.TrimSpace(s.Name)
+		if s.Name == "" {
+			return nil, fmt.Errorf("skills taxonomy: skill without name")
+		}
+		for i, alias := range append([]string{s.Name}, s.Aliases...) {
+			key := normalizeKey(alias)
+			if key == "" {
+				continue
+			}
+			if existing, ok := t.index[key]; ok && existing != s.Name {
+				return nil, fmt.Errorf("skills taxonomy: alias %q is used by both %q and %q", alias, existing, s.Name)
+			}
+			t.index[key] = s.Name
+			caseSensitive := s.CaseSensitive && i == 0
+			text := strings.TrimSpace(alias)
+			if !caseSensitive {
+				text = strings.ToLower(text)
+			}
+			t.terms = append(t.terms, term{text: text, canonical: s.Name, caseSensitive: caseSensitive})
+		}
+	}
+	// Alias terpanjang dicari dulu supaya "React Native" tidak terbaca sebagai "React"
+	sort.SliceStable(t.terms, func(i, j int) bool {
+		return len(t.terms[i].text) > len(t.terms[j].text)
+	})
+	return t, nil
+}
+
+// Len adalah jumlah skill kanonik di taxonomy
+func (t *Taxonomy) Len() int {
+	return len(t.skills)
+}
+
+// Checksum mengidentifikasi isi taxonomy; berubah kalau file taxonomy diubah
+func (t *Taxonomy) Checksum() string {
+	return t.checksum
+}
+
+// Normalize mengembalikan nama kanonik skill; skill yang tidak dikenal
+// dikembalikan apa adanya (dirapikan) dengan known = false
+func (t *Taxonomy) Normalize(raw string) (name string, known bool) {
+	raw = strings.Join(strings.Fields(raw), " ")
+	if canonical, ok := t.index[normalizeKey(raw)]; ok {
+		return canonical, true
+	}
+	return raw, false
+}
+
+// NormalizeAll menormalisasi daftar skill dan membuang duplikat (case-insensitive)
+func (t *Taxonomy) NormalizeAll(raw []string) []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, r := range raw {
+		name, _ := t.Normalize(r)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// Find mencari skill taxonomy di teks bebas (deskripsi job, teks CV) sebagai
+// kata utuh; hasilnya nama kanonik sesuai urutan kemunculan pertama
+func (t *Taxonomy) Find(text string) []string {
+	lower := strings.ToLower(text)
+	type hit struct {
+		name string
+		pos  int
+	}
+	var hits []hit
+	found := map[string]bool{}
+	// taken menandai bagian teks yang sudah cocok dengan alias yang lebih panjang
+	taken := make([]bool, len(text))
+	for _, tm := range t.terms {
+		haystack := lower
+		if tm.caseSensitive {
+			haystack = text
+		}
+		for _, pos := range wordIndexes(haystack, tm.text) {
+			if pos+len(tm.text) > len(taken) || taken[pos] || taken[pos+len(tm.text)-1] {
+				continue
+			}
+			for i := pos; i < pos+len(tm.text); i++ {
+				taken[i] = true
+			}
+			if !found[tm.canonical] {
+				found[tm.canonical] = true
+				hits = append(hits, hit{name: tm.canonical, pos: pos})
+			}
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].pos < hits[j].pos })
+	result := make([]string, 0, len(hits))
+	for _, h := range hits {
+		result = append(result, h.name)
+	}
+	return result
+}
+
+// ContainsWord melaporkan apakah word muncul di text sebagai kata utuh, dengan
+// aturan batas kata yang sama seperti Find. Pencocokan case-sensitive; samakan
+// huruf besar-kecil keduanya kalau perlu.
+func ContainsWord(text, word string) bool {
+	return len(wordIndexes(text, word)) > 0
+}
+
+// wordIndexes mengembalikan posisi semua kemunculan needle yang tidak menempel
+// pada huruf/angka lain dan tidak didahului titik, supaya "js" di "Express.js"
+// tidak terbaca sebagai skill sendiri. strings.ToLower bisa mengubah panjang byte untuk
+// sebagian karakter unicode; kemunculan di luar batas teks asli diabaikan.
+func wordIndexes(text, needle string) []int {
+	var result []int
+	if needle == "" {
+		return result
+	}
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], needle)
+		if i < 0 {
+			break
+		}
+		start, end := offset+i, offset+i+len(needle)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && before != '.' && !isWordRune(after) && !continuesTerm(text[end:]) {
+			result = append(result, start)
+		}
+		offset = start + 1
+	}
+	return result
+}
+
+// continuesTerm mencegah "C" cocok dengan "C++"/"C#" dan "vue" dengan "vue.js":
+// simbol yang biasa jadi bagian nama skill dianggap masih satu kata, tapi titik
+// di akhir kalimat tidak
+func continuesTerm(rest string) bool {
+	if strings.HasPrefix(rest, "+") || strings.HasPrefix(rest, "#") {
+		return true
+	}
+	if strings.HasPrefix(rest, ".") {
+		next, _ := utf8.DecodeRuneInString(rest[1:])
+		return unicode.IsLetter(next)
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// normalizeKey menyamakan huruf besar-kecil, spasi dan tanda kutip/kurung di
+// ujung; variasi penulisan lain (mis. "nodejs") harus didaftarkan sebagai alias
+func normalizeKey(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.Trim(s, " ,;:()[]\"'")
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,122 @@
+package skills
+
+import (
+	"slices"
+	"testing"
+)
+
+func defaultTaxonomyForTest(t *testing.T) *Taxonomy {
+	t.Helper()
+	tax, err := Load("")
+	if err != nil {
+		t.Fatalf("Load default taxonomy: %v", err)
+	}
+	return tax
+}
+
+func TestFind(t *testing.T) {
+	tax := defaultTaxonomyForTest(t)
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "alias of case-sensitive skill", text: "5 years of Golang and Express.js", want: []string{"Go", "Express"}},
+		{name: "alias in any case", text: "GOLANG, golang, Go Lang", want: []string{"Go"}},
+		{name: "case-sensitive name", text: "Built services in Go.", want: []string{"Go"}},
+		{name: "case-sensitive name as common word", text: "Ready to go the extra mile, GO team", want: []string{}},
+		{name: "dotted suffix is not a skill", text: "Node.js, Vue.js and Next.js", want: []string{"Node.js", "Vue.js", "Next.js"}},
+		{name: "plain js alias", text: "JS and TypeScript", want: []string{"JavaScript", "TypeScript"}},
+		{name: "c family", text: "C/C++/C#", want: []string{"C++", "C#"}},
+		{name: "c family aliases", text: "cpp and csharp", want: []string{"C++", "C#"}},
+		{name: "longest alias wins", text: "React Native", want: []string{"React Native"}},
+		{name: "react and react native", text: "React Native, later React", want: []string{"React Native", "React"}},
+		{name: "dot net", text: "ASP.NET and .NET Core", want: []string{".NET"}},
+		{name: "not inside other words", text: "Pythonic gopher, javascriptish", want: []string{}},
+		{name: "order of first appearance", text: "PostgreSQL, Docker, postgres", want: []string{"PostgreSQL", "Docker"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tax.Find(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("Find(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindSingleLetterSkill(t *testing.T) {
+	tax, err := New([]Skill{
+		{Name: "C", CaseSensitive: true},
+		{Name: "C++", Aliases: []string{"cpp"}},
+		{Name: "C#", Aliases: []string{"csharp"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "C, C++ and C#", want: []string{"C", "C++", "C#"}},
+		{text: "C++ only", want: []string{"C++"}},
+		{text: "C/C++", want: []string{"C", "C++"}},
+		{text: "vitamin c", want: []string{}},
+	}
+	for _, tt := range tests {
+		if got := tax.Find(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Find(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tax := defaultTaxonomyForTest(t)
+	tests := []struct {
+		raw       string
+		want      string
+		wantKnown bool
+	}{
+		{raw: "golang", want: "Go", wantKnown: true},
+		{raw: "GO", want: "Go", wantKnown: true},
+		{raw: " postgres ", want: "PostgreSQL", wantKnown: true},
+		{raw: "express.js", want: "Express", wantKnown: true},
+		{raw: "react  native", want: "React Native", wantKnown: true},
+		{raw: "Cobol  85", want: "Cobol 85", wantKnown: false},
+	}
+	for _, tt := range tests {
+		got, known := tax.Normalize(tt.raw)
+		if got != tt.want || known != tt.wantKnown {
+			t.Errorf("Normalize(%q) = %q, %t, want %q, %t", tt.raw, got, known, tt.want, tt.wantKnown)
+		}
+	}
+}
+
+func TestNewRejectsSharedAlias(t *testing.T) {
+	_, err := New([]Skill{
+		{Name: "PostgreSQL", Aliases: []string{"postgres"}},
+		{Name: "Postgres Pro", Aliases: []string{"Postgres"}},
+	})
+	if err == nil {
+		t.Fatal("New accepted an alias used by two skills")
+	}
+}
+
+func TestContainsWord(t *testing.T) {
+	tests := []struct {
+		text string
+		word string
+		want bool
+	}{
+		{text: "backend engineer", word: "backend", want: true},
+		{text: "backends", word: "backend", want: false},
+		{text: "c++ developer", word: "c", want: false},
+		{text: "express.js", word: "js", want: false},
+		{text: "payments (go)", word: "go", want: true},
+		{text: "anything", word: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := ContainsWord(tt.text, tt.word); got != tt.want {
+			t.Errorf("ContainsWord(%q, %q) = %t, want %t", tt.text, tt.word, got, tt.want)
+		}
+	}
+}
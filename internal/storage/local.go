@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage menyimpan file di filesystem di bawah satu root folder
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+func (s *LocalStorage) Name() string {
+	return DriverLocal
+}
+
+// Path mengembalikan lokasi file untuk key di bawah root
+func (s *LocalStorage) Path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put menulis ke file sementara lalu rename, supaya reader tidak pernah melihat
+// file yang setengah tertulis
+func (s *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	filePath, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filePath), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("write %s: wrote %d bytes, expected %d", key, written, size)
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	filePath, err := s.Path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return f, err
+}
+
+func (s *LocalStorage) Exists(ctx context.Context, key string) (bool, error) {
+	filePath, err := s.Path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	filePath, err := s.Path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/circuitbreaker"
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+)
+
+// unsignedPayload dipakai supaya body tidak perlu di-hash dua kali sebelum upload
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+type S3Options struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+// S3Storage adalah client minimal untuk API S3 (PUT/GET/HEAD/DELETE object)
+// dengan Signature V4. Cukup untuk AWS S3, MinIO dan storage S3-compatible lain
+// tanpa menambah dependency SDK. Semua request lewat circuit breaker "s3" yang
+// terlihat di /health/dependencies.
+type S3Storage struct {
+	endpoint *url.URL
+	opts     S3Options
+	client   *http.Client
+	breaker  *circuitbreaker.Breaker
+}
+
+func NewS3Storage(opts S3Options) (*S3Storage, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("S3_BUCKET is required for the s3 storage driver")
+	}
+	if opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, errors.New("S3_ACCESS_KEY and S3_SECRET_KEY are required for the s3 storage driver")
+	}
+	endpoint, err := url.Parse(strings.TrimRight(opts.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT %q", opts.Endpoint)
+	}
+	cbCfg := config.LoadCircuitBreakerConfig()
+	return &S3Storage{
+		endpoint: endpoint,
+		opts:     opts,
+		client:   &http.Client{Timeout: 2 * time.Minute},
+		breaker: circuitbreaker.New(DriverS3, circuitbreaker.Config{
+			Window:      cbCfg.Window,
+			MinRequests: cbCfg.MinRequests,
+			FailureRate: cbCfg.FailureRate,
+			Cooldown:    cbCfg.Cooldown,
+			MaxProbes:   cbCfg.MaxProbes,
+		}),
+	}, nil
+}
+
+func (s *S3Storage) Name() string {
+	return DriverS3
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Storage) Exists(ctx context.Context, key string) (bool, error) {
+	req, err := s.newRequest(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := s.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	return true, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	u := *s.endpoint
+	if s.opts.PathStyle {
+		u.Path = s.endpoint.Path + "/" + s.opts.Bucket + "/" + key
+	} else {
+		u.Host = s.opts.Bucket + "." + s.endpoint.Host
+		u.Path = s.endpoint.Path + "/" + key
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do menandatangani & mengirim request lewat circuit breaker; status non-2xx
+// dikembalikan sebagai error. 404 berarti storage sehat (object memang tidak
+// ada), pembatalan dari pemanggil tidak dihitung sebagai kegagalan.
+func (s *S3Storage) do(req *http.Request) (*http.Response, error) {
+	if err := s.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	s.sign(req, time.Now().UTC())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(req.Context().Err(), context.Canceled) {
+			s.breaker.Release()
+		} else {
+			s.breaker.Failure()
+		}
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		s.breaker.Success()
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		s.breaker.Success()
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, req.URL.Path)
+	}
+	s.breaker.Failure()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(detail)))
+}
+
+// sign menambahkan header Authorization AWS Signature Version 4
+func (s *S3Storage) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": unsignedPayload,
+		"x-amz-date":           amzDate,
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := date + "/" + s.opts.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.opts.SecretKey), date)
+	key = hmacSHA256(key, s.opts.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.opts.AccessKey, scope, signedHeaders, signature))
+}
+
+// uriEncode meng-encode path sesuai aturan SigV4 (RFC 3986, "/" tidak di-encode)
+func uriEncode(p string) string {
+	var b strings.Builder
+	for _, c := range []byte(p) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
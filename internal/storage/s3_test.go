@@ -0,0 +1,404 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/circuitbreaker"
+)
+
+const (
+	testAccessKey = "minio-access"
+	testSecretKey = "minio-secret"
+	testRegion    = "us-east-1"
+	testBucket    = "cv-analyzer"
+)
+
+// authPattern mengurai header Authorization SigV4
+var authPattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/s3/aws4_request, SignedHeaders=([^,]+), Signature=([0-9a-f]{64})$`)
+
+// fakeS3 adalah stand-in S3/MinIO di memori. Setiap request harus bertanda
+// tangan SigV4 yang valid untuk testSecretKey; signature dihitung ulang di sini
+// langsung dari request yang diterima server, terpisah dari S3Storage.sign.
+type fakeS3 struct {
+	pathStyle bool
+
+	mu       sync.Mutex
+	objects  map[string][]byte
+	types    map[string]string
+	requests []string
+}
+
+func newFakeS3(pathStyle bool) *fakeS3 {
+	return &fakeS3{pathStyle: pathStyle, objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if status, reason := f.verify(r); status != 0 {
+		http.Error(w, reason, status)
+		return
+	}
+
+	var key string
+	if f.pathStyle {
+		prefix := "/" + testBucket + "/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			http.Error(w, "NoSuchBucket", http.StatusNotFound)
+			return
+		}
+		key = strings.TrimPrefix(r.URL.Path, prefix)
+	} else {
+		if host, _, _ := net.SplitHostPort(r.Host); !strings.HasPrefix(host, testBucket+".") {
+			http.Error(w, "NoSuchBucket", http.StatusNotFound)
+			return
+		}
+		key = strings.TrimPrefix(r.URL.Path, "/")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+key)
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.ContentLength != int64(len(body)) {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// verify memeriksa header SigV4; status 0 berarti request valid
+func (f *fakeS3) verify(r *http.Request) (int, string) {
+	amzDate := r.Header.Get("X-Amz-Date")
+	if _, err := time.Parse("20060102T150405Z", amzDate); err != nil {
+		return http.StatusForbidden, "missing or invalid X-Amz-Date"
+	}
+	if got := r.Header.Get("X-Amz-Content-Sha256"); got != unsignedPayload {
+		return http.StatusForbidden, "X-Amz-Content-Sha256 = " + got
+	}
+	m := authPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+	if m == nil {
+		return http.StatusForbidden, "malformed Authorization: " + r.Header.Get("Authorization")
+	}
+	accessKey, date, region, signedHeaders, signature := m[1], m[2], m[3], m[4], m[5]
+	if accessKey != testAccessKey {
+		return http.StatusForbidden, "InvalidAccessKeyId"
+	}
+	if date != amzDate[:8] || region != testRegion {
+		return http.StatusForbidden, "credential scope mismatch"
+	}
+	if signedHeaders != "host;x-amz-content-sha256;x-amz-date" {
+		return http.StatusForbidden, "unexpected SignedHeaders " + signedHeaders
+	}
+
+	// Seperti S3, canonical URI adalah path yang sudah di-decode lalu di-encode
+	// ulang per RFC 3986 (hanya A-Z a-z 0-9 - _ . ~ dan "/" yang tidak di-encode)
+	var canonicalURI strings.Builder
+	for _, c := range []byte(r.URL.Path) {
+		if strings.IndexByte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_.~/", c) >= 0 {
+			canonicalURI.WriteByte(c)
+		} else {
+			canonicalURI.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+		}
+	}
+	canonicalRequest := r.Method + "\n" +
+		canonicalURI.String() + "\n" +
+		r.URL.RawQuery + "\n" +
+		"host:" + r.Host + "\n" +
+		"x-amz-content-sha256:" + unsignedPayload + "\n" +
+		"x-amz-date:" + amzDate + "\n\n" +
+		signedHeaders + "\n" +
+		unsignedPayload
+	hashed := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + date + "/" + region + "/s3/aws4_request\n" + hex.EncodeToString(hashed[:])
+
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	key := mac([]byte("AWS4"+testSecretKey), date)
+	key = mac(key, region)
+	key = mac(key, "s3")
+	key = mac(key, "aws4_request")
+	if want := hex.EncodeToString(mac(key, stringToSign)); !hmac.Equal([]byte(want), []byte(signature)) {
+		return http.StatusForbidden, "SignatureDoesNotMatch"
+	}
+	return 0, ""
+}
+
+func newTestS3Storage(t *testing.T, server *httptest.Server, secretKey string, pathStyle bool) *S3Storage {
+	t.Helper()
+	s, err := NewS3Storage(S3Options{
+		Endpoint:  server.URL,
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: secretKey,
+		PathStyle: pathStyle,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pathStyle {
+		// Virtual-hosted style memakai host <bucket>.127.0.0.1:<port>; koneksinya
+		// diarahkan ke server test
+		addr := server.Listener.Addr().String()
+		s.client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+		}
+	}
+	return s
+}
+
+func TestS3Storage(t *testing.T) {
+	for _, pathStyle := range []bool{true, false} {
+		name := "virtual-hosted"
+		if pathStyle {
+			name = "path-style"
+		}
+		t.Run(name, func(t *testing.T) {
+			fake := newFakeS3(pathStyle)
+			server := httptest.NewServer(fake)
+			defer server.Close()
+			s := newTestS3Storage(t, server, testSecretKey, pathStyle)
+			ctx := context.Background()
+
+			content := []byte("%PDF-1.4 candidate cv")
+			key := ContentKey(sha256Hex(content))
+
+			exists, err := s.Exists(ctx, key)
+			if err != nil || exists {
+				t.Fatalf("Exists before Put = %v, %v; want false, nil", exists, err)
+			}
+			if _, err := s.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Open before Put error = %v, want ErrNotFound", err)
+			}
+
+			if err := s.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+			fake.mu.Lock()
+			contentType := fake.types[key]
+			fake.mu.Unlock()
+			if contentType != "application/pdf" {
+				t.Errorf("stored Content-Type = %q, want application/pdf", contentType)
+			}
+
+			exists, err = s.Exists(ctx, key)
+			if err != nil || !exists {
+				t.Fatalf("Exists after Put = %v, %v; want true, nil", exists, err)
+			}
+			r, err := s.Open(ctx, key)
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			r.Close()
+			if err != nil || !bytes.Equal(got, content) {
+				t.Fatalf("Open content = %q, %v; want %q", got, err, content)
+			}
+
+			if err := s.Delete(ctx, key); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if err := s.Delete(ctx, key); err != nil {
+				t.Fatalf("Delete missing object: %v", err)
+			}
+			if exists, err := s.Exists(ctx, key); err != nil || exists {
+				t.Fatalf("Exists after Delete = %v, %v; want false, nil", exists, err)
+			}
+
+			// Key dengan karakter yang harus di-encode tetap lolos verifikasi signature
+			odd := "documents/ab/cv final+v2.pdf"
+			if err := s.Put(ctx, odd, strings.NewReader("cv"), 2, "application/pdf"); err != nil {
+				t.Fatalf("Put %q: %v", odd, err)
+			}
+			if exists, err := s.Exists(ctx, odd); err != nil || !exists {
+				t.Fatalf("Exists %q = %v, %v; want true, nil", odd, exists, err)
+			}
+
+			want := []string{"HEAD " + key, "GET " + key, "PUT " + key, "HEAD " + key, "GET " + key, "DELETE " + key, "DELETE " + key, "HEAD " + key, "PUT " + odd, "HEAD " + odd}
+			fake.mu.Lock()
+			defer fake.mu.Unlock()
+			if strings.Join(fake.requests, "\n") != strings.Join(want, "\n") {
+				t.Errorf("requests = %q, want %q", fake.requests, want)
+			}
+		})
+	}
+}
+
+func TestS3StorageRejectedSignature(t *testing.T) {
+	server := httptest.NewServer(newFakeS3(true))
+	defer server.Close()
+	s := newTestS3Storage(t, server, "wrong-secret", true)
+
+	err := s.Put(context.Background(), "documents/ab/abc", strings.NewReader("cv"), 2, "text/plain")
+	if err == nil || !strings.Contains(err.Error(), "status 403") || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Fatalf("Put with wrong secret error = %v, want 403 SignatureDoesNotMatch", err)
+	}
+	// 403 bukan not found: Exists harus mengembalikan error, bukan false
+	if _, err := s.Exists(context.Background(), "documents/ab/abc"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Fatalf("Exists with wrong secret error = %v, want a non-not-found error", err)
+	}
+}
+
+func TestS3StorageSignatureHeaders(t *testing.T) {
+	s, err := NewS3Storage(S3Options{
+		Endpoint:  "http://minio.local:9000",
+		Region:    testRegion,
+		Bucket:    testBucket,
+		AccessKey: testAccessKey,
+		SecretKey: testSecretKey,
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := s.newRequest(context.Background(), http.MethodGet, "documents/ab/a b+c", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.sign(req, time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC))
+
+	if got := req.Header.Get("X-Amz-Date"); got != "20250304T050607Z" {
+		t.Errorf("X-Amz-Date = %q", got)
+	}
+	if got := req.Header.Get("X-Amz-Content-Sha256"); got != unsignedPayload {
+		t.Errorf("X-Amz-Content-Sha256 = %q", got)
+	}
+	m := authPattern.FindStringSubmatch(req.Header.Get("Authorization"))
+	if m == nil {
+		t.Fatalf("Authorization = %q", req.Header.Get("Authorization"))
+	}
+	if m[1] != testAccessKey || m[2] != "20250304" || m[3] != testRegion || m[4] != "host;x-amz-content-sha256;x-amz-date" {
+		t.Errorf("Authorization = %q", req.Header.Get("Authorization"))
+	}
+	// Spasi & "+" di key harus di-encode di canonical URI
+	if got := uriEncode(req.URL.Path); got != "/"+testBucket+"/documents/ab/a%20b%2Bc" {
+		t.Errorf("canonical URI = %q", got)
+	}
+
+	// Request yang sama pada waktu yang sama menghasilkan signature yang sama
+	again, _ := s.newRequest(context.Background(), http.MethodGet, "documents/ab/a b+c", nil)
+	s.sign(again, time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC))
+	if again.Header.Get("Authorization") != req.Header.Get("Authorization") {
+		t.Error("signature is not deterministic")
+	}
+}
+
+func TestS3StorageCircuitBreaker(t *testing.T) {
+	var mu sync.Mutex
+	status, calls := http.StatusNotFound, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	s := newTestS3Storage(t, server, testSecretKey, true)
+	ctx := context.Background()
+	key := "documents/ab/abc"
+
+	// 404 adalah jawaban normal storage, bukan kegagalan
+	for i := 0; i < 10; i++ {
+		if exists, err := s.Exists(ctx, key); err != nil || exists {
+			t.Fatalf("Exists = %v, %v; want false, nil", exists, err)
+		}
+	}
+	if state := s.breaker.State(); state != circuitbreaker.StateClosed {
+		t.Fatalf("state after not-found responses = %s, want closed", state)
+	}
+
+	mu.Lock()
+	status, calls = http.StatusServiceUnavailable, 0
+	mu.Unlock()
+	for s.breaker.State() == circuitbreaker.StateClosed {
+		if _, err := s.Exists(ctx, key); err == nil {
+			t.Fatal("Exists on 503 returned no error")
+		}
+		if calls > 100 {
+			t.Fatal("breaker never opened")
+		}
+	}
+
+	mu.Lock()
+	before := calls
+	mu.Unlock()
+	if err := s.Put(ctx, key, strings.NewReader("cv"), 2, "text/plain"); !errors.Is(err, circuitbreaker.ErrOpen) {
+		t.Fatalf("Put while open = %v, want ErrOpen", err)
+	}
+	if calls != before {
+		t.Error("request was sent while the breaker is open")
+	}
+
+	found := false
+	for _, snapshot := range circuitbreaker.Snapshots() {
+		if snapshot.Name == DriverS3 {
+			found = snapshot.State == "open"
+		}
+	}
+	if !found {
+		t.Error("s3 breaker is not reported as open in circuitbreaker.Snapshots")
+	}
+}
+
+func TestS3StorageCanceledRequestReleasesBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	s := newTestS3Storage(t, server, testSecretKey, true)
+
+	for i := 0; i < 10; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			cancel()
+		}()
+		if _, err := s.Exists(ctx, "documents/ab/abc"); err == nil {
+			t.Fatal("Exists with canceled context returned no error")
+		}
+	}
+	if snapshot := s.breaker.Snapshot(); snapshot.State != "closed" || snapshot.Failures != 0 {
+		t.Errorf("snapshot = %+v, want closed without failures", snapshot)
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+// Storage menyimpan file upload berdasarkan key. Key selalu dibuat server
+// (lihat ContentKey), tidak pernah dari nama file kiriman client.
+type Storage interface {
+	Name() string
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// localPather diimplementasikan storage yang file-nya bisa dibaca langsung dari disk
+type localPather interface {
+	Path(key string) (string, error)
+}
+
+// New membuat Storage sesuai STORAGE_DRIVER
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case DriverLocal:
+		return NewLocalStorage(cfg.LocalDir), nil
+	case DriverS3:
+		return NewS3Storage(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// ContentKey adalah key untuk file dengan hash SHA-256 (hex) tertentu. Dua
+// prefix karakter pertama dipakai sebagai subfolder supaya satu folder tidak
+// berisi terlalu banyak file.
+func ContentKey(hash string) string {
+	return path.Join("documents", hash[:2], hash)
+}
+
+// validKey menolak key kosong, absolut atau yang keluar dari root (..)
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// LocalFile mengembalikan path file di disk untuk key tersebut. Storage lokal
+// dibaca langsung; storage lain di-download ke file sementara yang dihapus oleh
+// cleanup. Dipakai extractor (go-fitz & tesseract) yang butuh path file.
+func LocalFile(ctx context.Context, s Storage, key string) (string, func(), error) {
+	if local, ok := s.(localPather); ok {
+		filePath, err := local.Path(key)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := os.Stat(filePath); errors.Is(err, os.ErrNotExist) {
+			return "", nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return filePath, func() {}, nil
+	}
+
+	r, err := s.Open(ctx, key)
+	if err != nil {
+		return "", nil, err
+	}
+	defer r.Close()
+
+	tmp, err := os.CreateTemp("", "document-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("download %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return tmp.Name(), cleanup, nil
+}
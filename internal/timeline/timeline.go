@@ -0,0 +1,245 @@
+package timeline
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+)
+
+// MinGapMonths adalah panjang jeda minimal yang dilaporkan sebagai gap
+const MinGapMonths = 3
+
+// presentWords menandakan pekerjaan yang masih berjalan
+var presentWords = map[string]bool{
+	"present": true, "current": true, "currently": true, "now": true, "today": true, "ongoing": true,
+	"sekarang": true, "saat ini": true, "kini": true, "hingga kini": true, "hingga sekarang": true,
+}
+
+// monthNames berisi nama & singkatan bulan bahasa Inggris dan Indonesia
+var monthNames = map[string]time.Month{
+	"jan": time.January, "january": time.January, "januari": time.January,
+	"feb": time.February, "february": time.February, "februari": time.February, "peb": time.February, "pebruari": time.February,
+	"mar": time.March, "march": time.March, "maret": time.March,
+	"apr": time.April, "april": time.April,
+	"may": time.May, "mei": time.May,
+	"jun": time.June, "june": time.June, "juni": time.June,
+	"jul": time.July, "july": time.July, "juli": time.July,
+	"aug": time.August, "august": time.August, "agu": time.August, "agt": time.August, "agus": time.August, "agustus": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"oct": time.October, "october": time.October, "okt": time.October, "oktober": time.October,
+	"nov": time.November, "november": time.November, "nop": time.November, "nopember": time.November,
+	"dec": time.December, "december": time.December, "des": time.December, "desember": time.December,
+}
+
+var (
+	yearPattern      = regexp.MustCompile(`^(\d{4})$`)
+	yearMonthPattern = regexp.MustCompile(`^(\d{4})[-/.](\d{1,2})(?:[-/.]\d{1,2})?$`)
+	monthYearPattern = regexp.MustCompile(`^(?:\d{1,2}[-/.])?(\d{1,2})[-/.](\d{4})$`)
+	namedPattern     = regexp.MustCompile(`^(?:\d{1,2}\s+)?([a-z]+)\.?,?\s+(\d{4})$`)
+	// rangeSeparator memisahkan "2019 - 2021", "Jan 2019 to Mar 2021", "2019 s/d sekarang"
+	rangeSeparator = regexp.MustCompile(`\s*(?:\s-\s|-|\bto\b|\buntil\b|\bsampai\b|\bhingga\b|\bs/d\b|\bs\.d\.?)\s*`)
+)
+
+// Date adalah tanggal yang sudah dinormalisasi ke awal bulan
+type Date struct {
+	Month   time.Time
+	Present bool
+	// YearOnly berarti bulan tidak disebut; sebagai tanggal selesai dianggap Desember
+	YearOnly bool
+}
+
+// ParseDate membaca satu tanggal pengalaman kerja: "2021-03", "03/2021", "2021",
+// "Mar 2021", "Maret 2021", "Agustus, 2020", "Present", "Sekarang", dsb.
+func ParseDate(s string, now time.Time) (Date, bool) {
+	s = normalize(s)
+	if s == "" {
+		return Date{}, false
+	}
+	if presentWords[s] {
+		return Date{Month: monthStart(now.Year(), now.Month()), Present: true}, true
+	}
+	if m := yearPattern.FindStringSubmatch(s); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		return Date{Month: monthStart(year, time.January), YearOnly: true}, validYear(year)
+	}
+	if m := yearMonthPattern.FindStringSubmatch(s); m != nil {
+		return numericDate(m[1], m[2])
+	}
+	if m := monthYearPattern.FindStringSubmatch(s); m != nil {
+		return numericDate(m[2], m[1])
+	}
+	if m := namedPattern.FindStringSubmatch(s); m != nil {
+		month, ok := monthNames[m[1]]
+		if !ok {
+			return Date{}, false
+		}
+		year, _ := strconv.Atoi(m[2])
+		return Date{Month: monthStart(year, month)}, validYear(year)
+	}
+	return Date{}, false
+}
+
+// ParseRange membaca rentang dalam satu teks, mis. "2019–2021" atau
+// "Jan 2019 - Sekarang". LLM kadang menaruh rentang utuh di start_date.
+func ParseRange(s string, now time.Time) (Date, Date, bool) {
+	s = normalize(s)
+	for _, loc := range rangeSeparator.FindAllStringIndex(s, -1) {
+		start, okStart := ParseDate(s[:loc[0]], now)
+		end, okEnd := ParseDate(s[loc[1]:], now)
+		if okStart && okEnd {
+			return start, end, true
+		}
+	}
+	return Date{}, Date{}, false
+}
+
+// Build menyusun timeline dari riwayat kerja: tanggal dinormalisasi, stint yang
+// overlap digabung supaya tidak dihitung dua kali, lalu total & relevant years dan
+// gap dihitung. relevant menentukan pengalaman mana yang relevan dengan job.
+func Build(experiences []model.WorkExperience, relevant func(model.WorkExperience) bool, now time.Time) model.Timeline {
+	now = monthStart(now.Year(), now.Month())
+	result := model.Timeline{Stints: []model.Stint{}, Unparsed: []string{}, Gaps: []model.Gap{}, ComputedAt: now}
+
+	for _, exp := range experiences {
+		start, end, ok := experienceDates(exp, now)
+		label := exp.Title
+		if exp.Company != "" {
+			label += " @ " + exp.Company
+		}
+		if !ok || end.Month.Before(start.Month) || start.Month.After(now) {
+			result.Unparsed = append(result.Unparsed, strings.TrimSpace(label+" ("+exp.StartDate+" - "+exp.EndDate+")"))
+			continue
+		}
+		endMonth := end.Month
+		if end.YearOnly && !end.Present {
+			endMonth = monthStart(endMonth.Year(), time.December)
+		}
+		if endMonth.After(now) {
+			endMonth = now
+		}
+		result.Stints = append(result.Stints, model.Stint{
+			Title:    exp.Title,
+			Company:  exp.Company,
+			Start:    start.Month,
+			End:      endMonth,
+			Current:  end.Present,
+			Months:   monthsBetween(start.Month, endMonth),
+			Relevant: relevant != nil && relevant(exp),
+		})
+	}
+	sort.SliceStable(result.Stints, func(i, j int) bool {
+		return result.Stints[i].Start.Before(result.Stints[j].Start)
+	})
+
+	all := merge(result.Stints, func(model.Stint) bool { return true })
+	result.TotalMonths = sumMonths(all)
+	result.TotalYears = years(result.TotalMonths)
+	result.RelevantMonths = sumMonths(merge(result.Stints, func(s model.Stint) bool { return s.Relevant }))
+	result.RelevantYears = years(result.RelevantMonths)
+
+	for i := 1; i < len(all); i++ {
+		addGap(&result, all[i-1].end, all[i].start)
+	}
+	// Jeda dari pekerjaan terakhir sampai sekarang juga dihitung sebagai gap
+	if len(all) > 0 {
+		addGap(&result, all[len(all)-1].end, now.AddDate(0, 1, 0))
+	}
+	return result
+}
+
+// experienceDates membaca start & end date; rentang utuh di start_date juga diterima.
+// End kosong dianggap satu bulan saja (tanggal selesai tidak disebut).
+func experienceDates(exp model.WorkExperience, now time.Time) (Date, Date, bool) {
+	if strings.TrimSpace(exp.EndDate) == "" {
+		if start, end, ok := ParseRange(exp.StartDate, now); ok {
+			return start, end, true
+		}
+	}
+	start, ok := ParseDate(exp.StartDate, now)
+	if !ok {
+		return Date{}, Date{}, false
+	}
+	if strings.TrimSpace(exp.EndDate) == "" {
+		return start, Date{Month: start.Month}, true
+	}
+	end, ok := ParseDate(exp.EndDate, now)
+	return start, end, ok
+}
+
+type interval struct {
+	start, end time.Time
+}
+
+// merge menggabungkan stint yang overlap atau bersambung; stints harus sudah urut
+func merge(stints []model.Stint, include func(model.Stint) bool) []interval {
+	var merged []interval
+	for _, s := range stints {
+		if !include(s) {
+			continue
+		}
+		if n := len(merged); n > 0 && !s.Start.After(merged[n-1].end.AddDate(0, 1, 0)) {
+			if s.End.After(merged[n-1].end) {
+				merged[n-1].end = s.End
+			}
+			continue
+		}
+		merged = append(merged, interval{start: s.Start, end: s.End})
+	}
+	return merged
+}
+
+// addGap mencatat bulan-bulan kosong di antara prevEnd dan nextStart (eksklusif)
+func addGap(t *model.Timeline, prevEnd, nextStart time.Time) {
+	start := prevEnd.AddDate(0, 1, 0)
+	end := nextStart.AddDate(0, -1, 0)
+	months := monthsBetween(start, end)
+	if months >= MinGapMonths {
+		t.Gaps = append(t.Gaps, model.Gap{Start: start, End: end, Months: months})
+	}
+}
+
+func sumMonths(intervals []interval) int {
+	total := 0
+	for _, iv := range intervals {
+		total += monthsBetween(iv.start, iv.end)
+	}
+	return total
+}
+
+// monthsBetween menghitung jumlah bulan inklusif, mis. Jan–Mar = 3
+func monthsBetween(start, end time.Time) int {
+	return (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month()) + 1
+}
+
+func years(months int) float64 {
+	return math.Round(float64(months)/12*10) / 10
+}
+
+func numericDate(yearText, monthText string) (Date, bool) {
+	year, _ := strconv.Atoi(yearText)
+	month, _ := strconv.Atoi(monthText)
+	if month < 1 || month > 12 {
+		return Date{}, false
+	}
+	return Date{Month: monthStart(year, time.Month(month))}, validYear(year)
+}
+
+func validYear(year int) bool {
+	return year >= 1950 && year <= 2100
+}
+
+func monthStart(year int, month time.Month) time.Time {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// normalize menyamakan huruf, spasi dan tanda strip (en/em dash) sebelum parsing
+func normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer("–", "-", "—", "-", "‒", "-", "−", "-").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,244 @@
+package timeline
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+)
+
+var testNow = time.Date(2024, time.June, 15, 10, 0, 0, 0, time.UTC)
+
+func month(year int, m time.Month) time.Time {
+	return monthStart(year, m)
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Date
+		ok   bool
+	}{
+		{in: "2021-03", want: Date{Month: month(2021, time.March)}, ok: true},
+		{in: "03/2021", want: Date{Month: month(2021, time.March)}, ok: true},
+		{in: "15/03/2021", want: Date{Month: month(2021, time.March)}, ok: true},
+		{in: "Mar 2021", want: Date{Month: month(2021, time.March)}, ok: true},
+		{in: "Maret 2021", want: Date{Month: month(2021, time.March)}, ok: true},
+		{in: "Agustus, 2020", want: Date{Month: month(2020, time.August)}, ok: true},
+		{in: "Okt. 2018", want: Date{Month: month(2018, time.October)}, ok: true},
+		{in: "1 Desember 2019", want: Date{Month: month(2019, time.December)}, ok: true},
+		{in: "Pebruari 2017", want: Date{Month: month(2017, time.February)}, ok: true},
+		{in: "2021", want: Date{Month: month(2021, time.January), YearOnly: true}, ok: true},
+		{in: "Present", want: Date{Month: month(2024, time.June), Present: true}, ok: true},
+		{in: "Sekarang", want: Date{Month: month(2024, time.June), Present: true}, ok: true},
+		{in: " saat  ini ", want: Date{Month: month(2024, time.June), Present: true}, ok: true},
+		{in: "13/2021"},
+		{in: "Smarch 2021"},
+		{in: "1890"},
+		{in: ""},
+	}
+	for _, tt := range tests {
+		got, ok := ParseDate(tt.in, testNow)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("ParseDate(%q) = %+v, %t, want %+v, %t", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		in        string
+		wantStart Date
+		wantEnd   Date
+		ok        bool
+	}{
+		{
+			in:        "2019–2021",
+			wantStart: Date{Month: month(2019, time.January), YearOnly: true},
+			wantEnd:   Date{Month: month(2021, time.January), YearOnly: true},
+			ok:        true,
+		},
+		{
+			in:        "Jan 2019 - Sekarang",
+			wantStart: Date{Month: month(2019, time.January)},
+			wantEnd:   Date{Month: month(2024, time.June), Present: true},
+			ok:        true,
+		},
+		{
+			in:        "Maret 2019 s/d Juni 2020",
+			wantStart: Date{Month: month(2019, time.March)},
+			wantEnd:   Date{Month: month(2020, time.June)},
+			ok:        true,
+		},
+		{
+			in:        "2019 sampai sekarang",
+			wantStart: Date{Month: month(2019, time.January), YearOnly: true},
+			wantEnd:   Date{Month: month(2024, time.June), Present: true},
+			ok:        true,
+		},
+		{
+			// Strip di dalam tanggal dilewati sampai ketemu pemisah rentang
+			in:        "2020-01 - 2021-06",
+			wantStart: Date{Month: month(2020, time.January)},
+			wantEnd:   Date{Month: month(2021, time.June)},
+			ok:        true,
+		},
+		{in: "Jan 2019"},
+		{in: "someday - later"},
+	}
+	for _, tt := range tests {
+		start, end, ok := ParseRange(tt.in, testNow)
+		if ok != tt.ok || (ok && (start != tt.wantStart || end != tt.wantEnd)) {
+			t.Errorf("ParseRange(%q) = %+v, %+v, %t, want %+v, %+v, %t", tt.in, start, end, ok, tt.wantStart, tt.wantEnd, tt.ok)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name           string
+		experiences    []model.WorkExperience
+		wantStints     []model.Stint
+		wantTotal      int
+		wantTotalYears float64
+		wantRelevant   int
+		wantGaps       []model.Gap
+		wantUnparsed   []string
+	}{
+		{
+			name:        "indonesian months until sekarang",
+			experiences: []model.WorkExperience{{Title: "Backend Engineer", Company: "Acme", StartDate: "Januari 2023", EndDate: "Sekarang"}},
+			wantStints: []model.Stint{
+				{Title: "Backend Engineer", Company: "Acme", Start: month(2023, time.January), End: month(2024, time.June), Current: true, Months: 18, Relevant: true},
+			},
+			wantTotal:      18,
+			wantTotalYears: 1.5,
+			wantRelevant:   18,
+		},
+		{
+			name:        "range with s/d in start date",
+			experiences: []model.WorkExperience{{Title: "Support", StartDate: "Maret 2022 s/d Present"}},
+			wantStints: []model.Stint{
+				{Title: "Support", Start: month(2022, time.March), End: month(2024, time.June), Current: true, Months: 28},
+			},
+			wantTotal:      28,
+			wantTotalYears: 2.3,
+		},
+		{
+			name: "year-only end counts until december",
+			experiences: []model.WorkExperience{
+				{Title: "Backend Developer", StartDate: "2019–2021"},
+				{Title: "Backend Developer", StartDate: "Jan 2022", EndDate: "Present"},
+			},
+			wantStints: []model.Stint{
+				{Title: "Backend Developer", Start: month(2019, time.January), End: month(2021, time.December), Months: 36, Relevant: true},
+				{Title: "Backend Developer", Start: month(2022, time.January), End: month(2024, time.June), Current: true, Months: 30, Relevant: true},
+			},
+			wantTotal:      66,
+			wantTotalYears: 5.5,
+			wantRelevant:   66,
+		},
+		{
+			name: "overlapping stints counted once",
+			experiences: []model.WorkExperience{
+				{Title: "Freelance Designer", StartDate: "2021-06", EndDate: "2022-06"},
+				{Title: "Backend Engineer", StartDate: "2020-01", EndDate: "2021-12"},
+			},
+			wantStints: []model.Stint{
+				{Title: "Backend Engineer", Start: month(2020, time.January), End: month(2021, time.December), Months: 24, Relevant: true},
+				{Title: "Freelance Designer", Start: month(2021, time.June), End: month(2022, time.June), Months: 13},
+			},
+			wantTotal:      30,
+			wantTotalYears: 2.5,
+			wantRelevant:   24,
+			wantGaps:       []model.Gap{{Start: month(2022, time.July), End: month(2024, time.June), Months: 24}},
+		},
+		{
+			name: "gap between stints and trailing gap until now",
+			experiences: []model.WorkExperience{
+				{Title: "Backend Engineer", StartDate: "Jan 2018", EndDate: "Dec 2018"},
+				{Title: "Backend Engineer", StartDate: "Jun 2019", EndDate: "Des 2019"},
+			},
+			wantStints: []model.Stint{
+				{Title: "Backend Engineer", Start: month(2018, time.January), End: month(2018, time.December), Months: 12, Relevant: true},
+				{Title: "Backend Engineer", Start: month(2019, time.June), End: month(2019, time.December), Months: 7, Relevant: true},
+			},
+			wantTotal:      19,
+			wantTotalYears: 1.6,
+			wantRelevant:   19,
+			wantGaps: []model.Gap{
+				{Start: month(2019, time.January), End: month(2019, time.May), Months: 5},
+				{Start: month(2020, time.January), End: month(2024, time.June), Months: 54},
+			},
+		},
+		{
+			name: "short gap is not reported",
+			experiences: []model.WorkExperience{
+				{Title: "Backend Engineer", StartDate: "Jan 2020", EndDate: "Dec 2020"},
+				{Title: "Backend Engineer", StartDate: "Mar 2021", EndDate: "Sekarang"},
+			},
+			wantStints: []model.Stint{
+				{Title: "Backend Engineer", Start: month(2020, time.January), End: month(2020, time.December), Months: 12, Relevant: true},
+				{Title: "Backend Engineer", Start: month(2021, time.March), End: month(2024, time.June), Current: true, Months: 40, Relevant: true},
+			},
+			wantTotal:      52,
+			wantTotalYears: 4.3,
+			wantRelevant:   52,
+		},
+		{
+			name:        "empty end date counts one month",
+			experiences: []model.WorkExperience{{Title: "Intern", StartDate: "Mar 2024"}},
+			wantStints: []model.Stint{
+				{Title: "Intern", Start: month(2024, time.March), End: month(2024, time.March), Months: 1},
+			},
+			wantTotal:      1,
+			wantTotalYears: 0.1,
+			wantGaps:       []model.Gap{{Start: month(2024, time.April), End: month(2024, time.June), Months: 3}},
+		},
+		{
+			name: "unreadable, reversed and future dates",
+			experiences: []model.WorkExperience{
+				{Title: "Intern", Company: "Acme", StartDate: "sometime", EndDate: "later"},
+				{Title: "Analyst", StartDate: "2022", EndDate: "2020"},
+				{Title: "Backend Engineer", StartDate: "2030-01", EndDate: "Present"},
+			},
+			wantUnparsed: []string{"Intern @ Acme (sometime - later)", "Analyst (2022 - 2020)", "Backend Engineer (2030-01 - Present)"},
+		},
+	}
+	relevant := func(exp model.WorkExperience) bool {
+		return strings.Contains(exp.Title, "Backend")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Build(tt.experiences, relevant, testNow)
+			if !equalSlices(got.Stints, tt.wantStints) {
+				t.Errorf("stints = %+v, want %+v", got.Stints, tt.wantStints)
+			}
+			if got.TotalMonths != tt.wantTotal || got.TotalYears != tt.wantTotalYears {
+				t.Errorf("total = %d months / %v years, want %d / %v", got.TotalMonths, got.TotalYears, tt.wantTotal, tt.wantTotalYears)
+			}
+			if got.RelevantMonths != tt.wantRelevant {
+				t.Errorf("relevant months = %d, want %d", got.RelevantMonths, tt.wantRelevant)
+			}
+			if !equalSlices(got.Gaps, tt.wantGaps) {
+				t.Errorf("gaps = %+v, want %+v", got.Gaps, tt.wantGaps)
+			}
+			if !equalSlices(got.Unparsed, tt.wantUnparsed) {
+				t.Errorf("unparsed = %q, want %q", got.Unparsed, tt.wantUnparsed)
+			}
+			if !got.ComputedAt.Equal(month(2024, time.June)) {
+				t.Errorf("computed_at = %v, want start of the current month", got.ComputedAt)
+			}
+		})
+	}
+}
+
+// equalSlices menganggap slice nil dan kosong sama
+func equalSlices[T any](got, want []T) bool {
+	if len(got) == 0 && len(want) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(got, want)
+}
@@ -0,0 +1,422 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/dto"
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/fadilmartias/cv-analyzer/internal/repository"
+	"github.com/fadilmartias/cv-analyzer/internal/response"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCandidateNotFound = errors.New("candidate not found")
+	// ErrEvaluationNotFound dipakai kalau evaluasi yang dibandingkan bukan milik candidate
+	ErrEvaluationNotFound = errors.New("evaluation not found")
+	// ErrNothingToCompare berarti candidate belum punya dua evaluasi selesai
+	ErrNothingToCompare = errors.New("candidate needs at least two completed evaluations to compare")
+)
+
+type CandidateUsecase struct {
+	candidateRepo  *repository.CandidateRepository
+	evaluationRepo *repository.EvaluationRepository
+}
+
+func NewCandidateUsecase(candidateRepo *repository.CandidateRepository, evaluationRepo *repository.EvaluationRepository) *CandidateUsecase {
+	return &CandidateUsecase{candidateRepo: candidateRepo, evaluationRepo: evaluationRepo}
+}
+
+// CandidateFilter adalah filter List; email & telepon dinormalisasi dulu
+type CandidateFilter = repository.CandidateFilter
+
+// CandidateSummary adalah candidate beserta jumlah & waktu evaluasi terakhir
+type CandidateSummary = repository.CandidateSummary
+
+// Resolve mencari candidate dengan email/telepon profil, atau membuat yang
+// baru. Mengembalikan nil kalau CV tidak punya email maupun telepon.
+func (uc *CandidateUsecase) Resolve(profile *model.CandidateProfile) (*model.Candidate, error) {
+	if profile == nil {
+		return nil, nil
+	}
+	email := NormalizeEmail(profile.Contact.Email)
+	phone := NormalizePhone(profile.Contact.Phone)
+	if email == "" && phone == "" {
+		return nil, nil
+	}
+
+	candidate, err := uc.candidateRepo.FindByContact(email, phone)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		candidate = &model.Candidate{
+			Email:     email,
+			Phone:     phone,
+			Name:      strings.TrimSpace(profile.Name),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		created, err := uc.candidateRepo.Create(candidate)
+		if err != nil || created {
+			return candidate, err
+		}
+		// Dibuat worker lain di antara pencarian & insert
+		candidate, err = uc.candidateRepo.FindByContact(email, phone)
+		if err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Kontak yang belum tercatat & nama terbaru ikut disimpan. Telepon/email
+	// yang sudah dipakai candidate lain tidak ditimpa supaya tidak bentrok.
+	changed := false
+	if name := strings.TrimSpace(profile.Name); name != "" && name != candidate.Name {
+		candidate.Name = name
+		changed = true
+	}
+	if candidate.Email == "" && email != "" && uc.contactFree(email, "") {
+		candidate.Email = email
+		changed = true
+	}
+	if candidate.Phone == "" && phone != "" && uc.contactFree("", phone) {
+		candidate.Phone = phone
+		changed = true
+	}
+	if changed {
+		candidate.UpdatedAt = time.Now()
+		if err := uc.candidateRepo.Update(candidate); err != nil {
+			return nil, err
+		}
+	}
+	return candidate, nil
+}
+
+func (uc *CandidateUsecase) contactFree(email, phone string) bool {
+	_, err := uc.candidateRepo.FindByContact(email, phone)
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
+
+// LinkTask mengisi task.CandidateID dari profil task; task tidak disimpan di sini
+func (uc *CandidateUsecase) LinkTask(task *model.EvaluationTask) error {
+	candidate, err := uc.Resolve(task.Profile)
+	if err != nil || candidate == nil {
+		return err
+	}
+	task.CandidateID = &candidate.ID
+	return nil
+}
+
+// Backfill menautkan task lama yang profilnya sudah diparsing ke candidate
+func (uc *CandidateUsecase) Backfill() error {
+	tasks, err := uc.evaluationRepo.FindUnlinkedProfiles()
+	if err != nil {
+		return err
+	}
+	linked := 0
+	for _, task := range tasks {
+		candidate, err := uc.Resolve(task.Profile)
+		if err != nil {
+			return err
+		}
+		if candidate == nil {
+			continue
+		}
+		if err := uc.evaluationRepo.SetCandidate(task.ID, candidate.ID); err != nil {
+			return err
+		}
+		linked++
+	}
+	if linked > 0 {
+		log.Printf("Linked %d evaluation tasks to candidates", linked)
+	}
+	return nil
+}
+
+func (uc *CandidateUsecase) Get(id uuid.UUID) (*CandidateSummary, error) {
+	candidate, err := uc.candidateRepo.FindSummaryByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCandidateNotFound
+	}
+	return candidate, err
+}
+
+func (uc *CandidateUsecase) List(filter CandidateFilter, page, pageSize int) ([]CandidateSummary, *response.Pagination, error) {
+	filter.Email = NormalizeEmail(filter.Email)
+	filter.Phone = NormalizePhone(filter.Phone)
+	offset := (page - 1) * pageSize
+	candidates, total, err := uc.candidateRepo.ListCandidates(filter, offset, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return candidates, response.NewPagination(page, pageSize, len(candidates), total), nil
+}
+
+// Evaluations mengembalikan riwayat evaluasi candidate dari yang terlama. Tiap
+// evaluasi selesai dibandingkan dengan evaluasi selesai sebelumnya untuk job yang
+// sama (evaluasi tanpa job_id dibandingkan sesamanya).
+func (uc *CandidateUsecase) Evaluations(id uuid.UUID) ([]dto.CandidateEvaluationDTO, error) {
+	if _, err := uc.Get(id); err != nil {
+		return nil, err
+	}
+	tasks, err := uc.evaluationRepo.ListTasksByCandidate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]dto.CandidateEvaluationDTO, 0, len(tasks))
+	previous := map[string]*model.EvaluationTask{}
+	for i := range tasks {
+		task := &tasks[i]
+		entry := dto.CandidateEvaluationDTO{EvaluationSummaryDTO: dto.NewEvaluationSummaryDTO(*task)}
+		if task.Status == model.TaskStatusCompleted {
+			key := jobKey(task.JobID)
+			if prev, ok := previous[key]; ok {
+				change := scoreChange(prev, task)
+				entry.Change = &change
+			}
+			previous[key] = task
+		}
+		history = append(history, entry)
+	}
+	return history, nil
+}
+
+// Compare membandingkan dua evaluasi selesai milik candidate. fromID/toID nil
+// berarti dua evaluasi selesai terakhir.
+func (uc *CandidateUsecase) Compare(id uuid.UUID, fromID, toID *uuid.UUID) (*dto.EvaluationComparisonDTO, error) {
+	if _, err := uc.Get(id); err != nil {
+		return nil, err
+	}
+	tasks, err := uc.evaluationRepo.ListTasksByCandidate(id)
+	if err != nil {
+		return nil, err
+	}
+	var completed []*model.EvaluationTask
+	byID := map[uuid.UUID]*model.EvaluationTask{}
+	for i := range tasks {
+		if tasks[i].Status == model.TaskStatusCompleted {
+			completed = append(completed, &tasks[i])
+			byID[tasks[i].ID] = &tasks[i]
+		}
+	}
+
+	pick := func(id *uuid.UUID, fallback int) (*model.EvaluationTask, error) {
+		if id != nil {
+			task, ok := byID[*id]
+			if !ok {
+				return nil, ErrEvaluationNotFound
+			}
+			return task, nil
+		}
+		if fallback < 0 {
+			return nil, ErrNothingToCompare
+		}
+		return completed[fallback], nil
+	}
+	from, err := pick(fromID, len(completed)-2)
+	if err != nil {
+		return nil, err
+	}
+	to, err := pick(toID, len(completed)-1)
+	if err != nil {
+		return nil, err
+	}
+	if from.ID == to.ID {
+		return nil, ErrNothingToCompare
+	}
+	return compareEvaluations(from, to), nil
+}
+
+func compareEvaluations(from, to *model.EvaluationTask) *dto.EvaluationComparisonDTO {
+	result := &dto.EvaluationComparisonDTO{
+		From:          dto.NewEvaluationSummaryDTO(*from),
+		To:            dto.NewEvaluationSummaryDTO(*to),
+		SameJob:       jobKey(from.JobID) == jobKey(to.JobID),
+		Change:        scoreChange(from, to),
+		Parameters:    parameterChanges(from, to),
+		SkillsAdded:   []string{},
+		SkillsRemoved: []string{},
+		SkillCoverage: []dto.SkillCoverageChangeDTO{},
+	}
+
+	fromSkills, toSkills := candidateSkills(from), candidateSkills(to)
+	result.SkillsAdded = difference(toSkills, fromSkills)
+	result.SkillsRemoved = difference(fromSkills, toSkills)
+
+	if from.Timeline != nil && to.Timeline != nil {
+		result.Experience = &dto.ExperienceChangeDTO{
+			TotalYears:    round2(to.Timeline.TotalYears - from.Timeline.TotalYears),
+			RelevantYears: round2(to.Timeline.RelevantYears - from.Timeline.RelevantYears),
+		}
+	}
+
+	// Coverage skill dibandingkan untuk job yang ada di kedua evaluasi
+	if from.SkillGap != nil && to.SkillGap != nil {
+		previous := map[uuid.UUID]model.JobSkillGap{}
+		for _, gap := range from.SkillGap.Jobs {
+			previous[gap.JobID] = gap
+		}
+		for _, gap := range to.SkillGap.Jobs {
+			prev, ok := previous[gap.JobID]
+			if !ok {
+				continue
+			}
+			result.SkillCoverage = append(result.SkillCoverage, dto.SkillCoverageChangeDTO{
+				JobID:    gap.JobID,
+				JobTitle: gap.JobTitle,
+				From:     prev.Coverage,
+				To:       gap.Coverage,
+				Change:   round2(gap.Coverage - prev.Coverage),
+				Gained:   difference(gap.Matched, prev.Matched),
+			})
+		}
+	}
+	return result
+}
+
+func scoreChange(from, to *model.EvaluationTask) dto.ScoreChangeDTO {
+	return dto.ScoreChangeDTO{
+		PreviousID:   from.ID,
+		CvMatchRate:  round2(to.CvMatchRate - from.CvMatchRate),
+		ProjectScore: round2(to.ProjectScore - from.ProjectScore),
+	}
+}
+
+// parameterChanges membandingkan skor rubric per parameter dari score_details.
+// Parameter yang hanya ada di salah satu rubric tetap ditampilkan dengan nilai kosong.
+func parameterChanges(from, to *model.EvaluationTask) []dto.ParameterChangeDTO {
+	fromScores, toScores := componentScores(from), componentScores(to)
+	keys := map[[2]string]bool{}
+	for k := range fromScores {
+		keys[k] = true
+	}
+	for k := range toScores {
+		keys[k] = true
+	}
+	ordered := make([][2]string, 0, len(keys))
+	for k := range keys {
+		ordered = append(ordered, k)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i][0] != ordered[j][0] {
+			return ordered[i][0] < ordered[j][0]
+		}
+		return ordered[i][1] < ordered[j][1]
+	})
+
+	changes := make([]dto.ParameterChangeDTO, 0, len(ordered))
+	for _, k := range ordered {
+		change := dto.ParameterChangeDTO{Section: k[0], Parameter: k[1]}
+		if v, ok := fromScores[k]; ok {
+			change.From = &v
+		}
+		if v, ok := toScores[k]; ok {
+			change.To = &v
+		}
+		if change.From != nil && change.To != nil {
+			diff := round2(*change.To - *change.From)
+			change.Change = &diff
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// componentScores membaca skor rubric 1-5 per (section, parameter) dari score_details
+func componentScores(task *model.EvaluationTask) map[[2]string]float64 {
+	scores := map[[2]string]float64{}
+	if task.ScoreDetails == "" {
+		return scores
+	}
+	var details dto.ScoreDetails
+	if err := json.Unmarshal([]byte(task.ScoreDetails), &details); err != nil {
+		log.Printf("Invalid score_details for task %s: %v", task.ID, err)
+		return scores
+	}
+	for _, c := range details.CV.Components {
+		scores[[2]string{model.RubricSectionCV, c.Parameter}] = c.Score
+	}
+	for _, c := range details.ProjectReport.Components {
+		scores[[2]string{model.RubricSectionProjectReport, c.Parameter}] = c.Score
+	}
+	return scores
+}
+
+// candidateSkills memakai skill yang sudah dinormalisasi taxonomy kalau ada
+func candidateSkills(task *model.EvaluationTask) []string {
+	if task.SkillGap != nil {
+		return task.SkillGap.CandidateSkills
+	}
+	if task.Profile != nil {
+		return task.Profile.Skills
+	}
+	return nil
+}
+
+// difference mengembalikan isi a yang tidak ada di b (case-insensitive)
+func difference(a, b []string) []string {
+	inB := map[string]bool{}
+	for _, v := range b {
+		inB[strings.ToLower(v)] = true
+	}
+	result := []string{}
+	for _, v := range a {
+		if !inB[strings.ToLower(v)] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func jobKey(jobID *uuid.UUID) string {
+	if jobID == nil {
+		return ""
+	}
+	return jobID.String()
+}
+
+// NormalizeEmail menyamakan email untuk identifikasi candidate; email yang
+// tidak valid dianggap kosong
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return ""
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return ""
+	}
+	return email
+}
+
+// NormalizePhone menyamakan nomor telepon ke digit saja dengan kode negara.
+// Nomor lokal Indonesia ("0812...") diubah ke "62812..."; nomor dengan kurang
+// dari 9 atau lebih dari 15 digit dianggap kosong.
+func NormalizePhone(phone string) string {
+	var b strings.Builder
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	switch {
+	case strings.HasPrefix(digits, "620"):
+		digits = "62" + digits[3:]
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		digits = "62" + digits[1:]
+	}
+	if len(digits) < 9 || len(digits) > 15 {
+		return ""
+	}
+	return digits
+}
@@ -0,0 +1,259 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fadilmartias/cv-analyzer/internal/extractor"
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/fadilmartias/cv-analyzer/internal/repository"
+	"github.com/fadilmartias/cv-analyzer/internal/service"
+	"github.com/fadilmartias/cv-analyzer/internal/storage"
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+)
+
+var ErrDocumentNotFound = errors.New("document not found")
+
+// errStorageUnavailable menandakan file dokumen tidak bisa diambil dari storage
+// karena gangguan (bukan karena file-nya tidak ada), jadi task boleh di-retry
+var errStorageUnavailable = errors.New("storage unavailable")
+
+// maxFilenameLength sama dengan panjang kolom documents.filename
+const maxFilenameLength = 255
+
+type DocumentUsecase struct {
+	documentRepo *repository.DocumentRepository
+	storage      storage.Storage
+	embedder     service.Embedder
+}
+
+func NewDocumentUsecase(documentRepo *repository.DocumentRepository, storage storage.Storage, embedder service.Embedder) *DocumentUsecase {
+	return &DocumentUsecase{documentRepo: documentRepo, storage: storage, embedder: embedder}
+}
+
+// StagedUpload adalah file upload yang sudah divalidasi di file temp tapi
+// belum disimpan; Close harus dipanggil setelah selesai dipakai
+type StagedUpload struct {
+	path     string
+	filename string
+	hash     string
+	mimeType string
+	size     int64
+}
+
+// Close menghapus file temp upload
+func (s *StagedUpload) Close() error {
+	return os.Remove(s.path)
+}
+
+// Stage menulis file upload ke temp dan memvalidasinya tanpa menyimpan apa pun,
+// supaya semua file satu request bisa divalidasi sebelum ada yang disimpan.
+// File dengan format yang tidak didukung ditolak dengan
+// extractor.ErrUnsupportedType, file yang gagal validasi dengan
+// extractor.ErrInvalidFile.
+func (uc *DocumentUsecase) Stage(filename string, r io.Reader) (*StagedUpload, error) {
+	// File ditulis ke temp dulu: hash & MIME type baru diketahui setelah
+	// seluruh isi dibaca
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer tmp.Close()
+	staged := &StagedUpload{path: tmp.Name(), filename: sanitizeFilename(filename)}
+
+	hasher := sha256.New()
+	staged.size, err = io.Copy(io.MultiWriter(tmp, hasher), r)
+	if err != nil {
+		staged.Close()
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	staged.hash = hex.EncodeToString(hasher.Sum(nil))
+
+	// Format ditentukan dari isi file (magic bytes) dan harus cocok dengan
+	// ekstensinya; PDF, gambar & arsip juga dicek terhadap batas UPLOAD_*
+	staged.mimeType, err = extractor.Validate(staged.path, staged.filename)
+	if err != nil {
+		staged.Close()
+		return nil, err
+	}
+	return staged, nil
+}
+
+// Store menyimpan file yang sudah di-Stage di storage dengan key dari hash
+// SHA-256 isinya, lalu mencatat nama asli, ukuran dan MIME type-nya di tabel
+// documents
+func (uc *DocumentUsecase) Store(ctx context.Context, staged *StagedUpload) (*model.Document, error) {
+	key := storage.ContentKey(staged.hash)
+	exists, err := uc.storage.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		f, err := os.Open(staged.path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if err := uc.storage.Put(ctx, key, f, staged.size, staged.mimeType); err != nil {
+			return nil, fmt.Errorf("store document: %w", err)
+		}
+	}
+
+	doc := model.Document{
+		Hash:       staged.hash,
+		StorageKey: key,
+		Filename:   staged.filename,
+		Size:       staged.size,
+		MIMEType:   staged.mimeType,
+		CreatedAt:  time.Now(),
+	}
+	if err := uc.documentRepo.Create(&doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (uc *DocumentUsecase) Get(id uuid.UUID) (*model.Document, error) {
+	doc, err := uc.documentRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDocumentNotFound
+	}
+	return doc, err
+}
+
+// GetMany mengambil beberapa dokumen per ID; ID nil diabaikan
+func (uc *DocumentUsecase) GetMany(ids ...*uuid.UUID) (map[uuid.UUID]model.Document, error) {
+	var list []uuid.UUID
+	for _, id := range ids {
+		if id != nil {
+			list = append(list, *id)
+		}
+	}
+	return uc.documentRepo.FindByIDs(list)
+}
+
+// LocalFile mengembalikan path file dokumen di disk untuk extractor; cleanup
+// harus dipanggil setelah selesai dipakai
+func (uc *DocumentUsecase) LocalFile(ctx context.Context, doc *model.Document) (string, func(), error) {
+	return storage.LocalFile(ctx, uc.storage, doc.StorageKey)
+}
+
+// Extract mengekstrak teks dokumen. Hasil ekstraksi di-cache per hash file &
+// extractor.CacheKey, jadi file yang di-upload ulang tidak di-OCR lagi.
+func (uc *DocumentUsecase) Extract(ctx context.Context, doc *model.Document, opts extractor.Options) (*extractor.Document, error) {
+	key := extractor.CacheKey(opts)
+	cached, err := uc.documentRepo.FindExtraction(doc.Hash, key)
+	if err == nil {
+		var result extractor.Document
+		if err := json.Unmarshal([]byte(cached.Result), &result); err == nil {
+			result.Text = cached.Text
+			return &result, nil
+		}
+		log.Printf("Cached extraction for document %s is unreadable, extracting again", doc.Hash)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Read extraction cache for document %s failed: %v", doc.Hash, err)
+	}
+
+	path, cleanup, err := uc.LocalFile(ctx, doc)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", errStorageUnavailable, err)
+	}
+	defer cleanup()
+
+	result, err := extractor.Extract(ctx, path, doc.Filename, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gagal menulis cache tidak menggagalkan ekstraksi
+	if resultJSON, err := json.Marshal(result); err == nil {
+		extraction := model.DocumentExtraction{
+			Hash:      doc.Hash,
+			Key:       key,
+			Text:      result.Text,
+			Result:    string(resultJSON),
+			CreatedAt: time.Now(),
+		}
+		if err := uc.documentRepo.SaveExtraction(&extraction); err != nil {
+			log.Printf("Save extraction cache for document %s failed: %v", doc.Hash, err)
+		}
+	}
+	return result, nil
+}
+
+// Embedding mengembalikan embedding teks dokumen hasil ekstraksi dengan opts.
+// Embedding di-cache per hash file, model embedding & key ekstraksi, jadi file
+// yang di-upload ulang tidak di-embed lagi, tapi teks dari setting OCR lain
+// di-embed ulang.
+func (uc *DocumentUsecase) Embedding(ctx context.Context, doc *model.Document, opts extractor.Options, text string) ([]float32, error) {
+	embeddingModel := uc.embedder.EmbeddingModelID()
+	extractionKey := extractor.CacheKey(opts)
+	cached, err := uc.documentRepo.FindEmbedding(doc.Hash, embeddingModel, extractionKey)
+	if err == nil {
+		return cached.Embedding.Slice(), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Read embedding cache for document %s failed: %v", doc.Hash, err)
+	}
+
+	result, err := uc.embedder.GenerateEmbedding(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	embedding := model.DocumentEmbedding{
+		Hash:          doc.Hash,
+		Model:         embeddingModel,
+		ExtractionKey: extractionKey,
+		Embedding:     pgvector.NewVector(result),
+		CreatedAt:     time.Now(),
+	}
+	if err := uc.documentRepo.SaveEmbedding(&embedding); err != nil {
+		log.Printf("Save embedding cache for document %s failed: %v", doc.Hash, err)
+	}
+	return result, nil
+}
+
+// sanitizeFilename hanya menyisakan nama file (tanpa path) tanpa karakter
+// kontrol, dipotong ke maxFilenameLength byte
+func sanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = filepath.Base(name)
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" || name == ".." {
+		return "upload"
+	}
+	// Nama yang terlalu panjang dipotong di bagian sebelum ekstensi
+	ext := filepath.Ext(name)
+	if len(ext) > 16 {
+		ext = ""
+	}
+	stem := strings.TrimSuffix(name, ext)
+	for len(stem)+len(ext) > maxFilenameLength {
+		_, size := utf8.DecodeLastRuneInString(stem)
+		stem = stem[:len(stem)-size]
+	}
+	return stem + ext
+}
@@ -0,0 +1,774 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+	"github.com/fadilmartias/cv-analyzer/internal/dto"
+	"github.com/fadilmartias/cv-analyzer/internal/extractor"
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/fadilmartias/cv-analyzer/internal/service"
+	"github.com/fadilmartias/cv-analyzer/internal/skills"
+	"github.com/fadilmartias/cv-analyzer/internal/timeline"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultCaseStudyBrief dipakai stage project_evaluation kalau job tidak punya case_study_brief
+const DefaultCaseStudyBrief = `Build a backend service that automates the initial screening of a job application. The service receives a candidate CV and a project report, evaluates them against a job vacancy and this case study brief, and produces a structured evaluation report.
+
+Requirements:
+- Endpoints to upload the CV and project report, to start an evaluation asynchronously (returning an id immediately) and to fetch the status and result of an evaluation.
+- Ingest job descriptions and scoring references into a vector database and retrieve the relevant context when building prompts (RAG).
+- An LLM chaining pipeline: CV evaluation, project report evaluation, and a final analysis that synthesizes both.
+- Handle long-running processes and failures of the LLM API gracefully: timeouts, rate limits, retries with back-off, and controlled randomness of LLM output.
+- A clear README explaining the design choices, trade-offs and limitations.`
+
+var pipelineStages = []string{model.StageExtract, model.StageCVEvaluation, model.StageProjectEvaluation, model.StageSynthesis}
+
+// evaluationPipeline menyimpan state pipeline untuk satu task, termasuk stage
+// yang sudah selesai di attempt sebelumnya
+type evaluationPipeline struct {
+	uc     *EvaluationUsecase
+	task   *model.EvaluationTask
+	stages map[string]*model.EvaluationStage
+}
+
+func (uc *EvaluationUsecase) newPipeline(task *model.EvaluationTask) (*evaluationPipeline, error) {
+	stages, err := uc.stageRepo.FindByTask(task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("find stages: %w", err)
+	}
+	p := &evaluationPipeline{uc: uc, task: task, stages: map[string]*model.EvaluationStage{}}
+	for i := range stages {
+		p.stages[stages[i].Stage] = &stages[i]
+	}
+	return p, nil
+}
+
+// pipelineVersion dinaikkan setiap kali prompt, perhitungan skor atau deteksi
+// skill berubah, supaya hasil evaluasi lama tidak dipakai ulang (EVALUATION_REUSE_RESULTS)
+const pipelineVersion = 5
+
+// ExtractionError menandakan file yang di-upload tidak bisa diekstrak (format tidak
+// didukung, isi kosong atau terlalu pendek). Error ini permanen: worker tidak me-retry task-nya.
+type ExtractionError struct {
+	Field string
+	Err   error
+}
+
+func (e *ExtractionError) Error() string {
+	return fmt.Sprintf("extract %s: %v", e.Field, e.Err)
+}
+
+func (e *ExtractionError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ExtractionError) Permanent() bool {
+	return true
+}
+
+// EvaluateTask menjalankan pipeline task: ekstraksi teks → embedding & konteks job →
+// evaluasi LLM berantai (extract → cv_evaluation → project_evaluation → synthesis).
+// Hasil tiap tahap disimpan, jadi saat retry tahap yang sudah selesai tidak dijalankan ulang.
+func (uc *EvaluationUsecase) EvaluateTask(ctx context.Context, task *model.EvaluationTask) error {
+	// Input yang sama persis dengan task yang sudah selesai tidak dievaluasi ulang
+	if reused, err := uc.reuseResult(task); err != nil || reused {
+		return err
+	}
+
+	// 1️⃣ Ekstraksi teks dari file yang di-upload
+	if task.CV == "" || task.Report == "" {
+		task.SetStatus(model.TaskStatusExtracting)
+		if err := uc.extractDocuments(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	rubric, err := uc.resolveRubric(task)
+	if err != nil {
+		return fmt.Errorf("resolve rubric: %w", err)
+	}
+
+	// 2️⃣ Embedding CV & pencarian job konteks (dilewati kalau sudah tersimpan)
+	jobs, err := uc.storedContextJobs(task)
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		task.SetStatus(model.TaskStatusEmbedding)
+		if err := uc.evaluationRepo.UpdateTask(task); err != nil {
+			return err
+		}
+		if jobs, err = uc.contextJobs(ctx, task); err != nil {
+			return err
+		}
+	}
+
+	// Konteks & rubric disimpan sebelum stage dijalankan supaya retry memakai job yang sama
+	contextJobs := make([]model.ContextJob, 0, len(jobs))
+	for _, j := range jobs {
+		contextJobs = append(contextJobs, model.ContextJob{ID: j.ID, Title: j.Title})
+	}
+	if contextJSON, err := json.Marshal(contextJobs); err == nil {
+		task.ContextJobs = string(contextJSON)
+	}
+	if rubric.ID != uuid.Nil {
+		task.RubricID = &rubric.ID
+	}
+	task.SetStatus(model.TaskStatusEvaluating)
+	if err := uc.evaluationRepo.UpdateTask(task); err != nil {
+		return err
+	}
+
+	p, err := uc.newPipeline(task)
+	if err != nil {
+		return err
+	}
+
+	// 3️⃣ Evaluasi LLM berantai, dimulai dari extract fakta terstruktur dari CV
+	profile, err := runStage(ctx, p, model.StageExtract, extractPrompt(task), dto.CandidateProfileSchema(), dto.ParseCandidateProfile)
+	if err != nil {
+		return err
+	}
+	// Email, telepon & link dilengkapi regex dari teks CV; profil disimpan
+	// sekarang supaya bisa difilter walaupun stage berikutnya gagal
+	dto.ApplyProfileFallbacks(profile, task.CV)
+	task.Profile = profile
+	// Lama pengalaman & gap dihitung di Go dari tanggal kerja, bukan ditebak LLM
+	history := timeline.Build(profile.Experiences, uc.relevantExperience(jobs), time.Now())
+	task.Timeline = &history
+	// Skill kandidat dibandingkan dengan skill wajib & nice-to-have tiap job
+	task.SkillGap = uc.skillGap(jobs, profile, task.CV)
+	// Task ditautkan ke candidate dari email/telepon CV. Gagal menautkan tidak
+	// menggagalkan evaluasi; task yang terlewat ditautkan ulang saat startup.
+	if err := uc.candidates.LinkTask(task); err != nil {
+		log.Printf("Link task %s to candidate failed: %v", task.ID, err)
+	}
+	if err := uc.evaluationRepo.UpdateTask(task); err != nil {
+		return err
+	}
+
+	// Nilai CV terhadap job
+	cv, err := runStage(ctx, p, model.StageCVEvaluation, cvEvaluationPrompt(task, jobs, profile, task.Timeline, task.SkillGap, rubric),
+		dto.SectionEvaluationSchema(rubric.CVParameters),
+		func(text string) (*dto.SectionEvaluation, error) {
+			return dto.ParseSectionEvaluation(text, rubric.CVParameters)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Nilai project report terhadap case study brief
+	project, err := runStage(ctx, p, model.StageProjectEvaluation, projectEvaluationPrompt(task, jobs, rubric),
+		dto.SectionEvaluationSchema(rubric.ProjectParameters),
+		func(text string) (*dto.SectionEvaluation, error) {
+			return dto.ParseSectionEvaluation(text, rubric.ProjectParameters)
+		})
+	if err != nil {
+		return err
+	}
+
+	// Skor akhir dihitung di Go dari skor rubric, bukan oleh LLM
+	evaluation := dto.EvaluationBreakdown{CV: cv.Scores, ProjectReport: project.Scores}
+	scores := computeScores(evaluation, rubric)
+
+	// Sintesis overall summary dari hasil stage sebelumnya
+	synthesis, err := runStage(ctx, p, model.StageSynthesis, synthesisPrompt(task, jobs, profile, cv, project, scores), dto.SynthesisSchema(), dto.ParseSynthesis)
+	if err != nil {
+		return err
+	}
+
+	breakdown, err := json.Marshal(evaluation)
+	if err != nil {
+		return err
+	}
+	scoreDetails, err := json.Marshal(scores)
+	if err != nil {
+		return err
+	}
+
+	// 4️⃣ Update task
+	task.CvMatchRate = scores.CV.Score
+	task.CvFeedback = cv.Feedback
+	task.ProjectScore = scores.ProjectReport.Score
+	task.ProjectFeedback = project.Feedback
+	task.OverallSummary = synthesis.OverallSummary
+	task.Breakdown = string(breakdown)
+	task.ScoreDetails = string(scoreDetails)
+	task.Provider, task.Model = p.providers()
+	task.SetStatus(model.TaskStatusCompleted)
+	return uc.evaluationRepo.UpdateTask(task)
+}
+
+// reuseResult mengisi task.ResultKey lalu, kalau EVALUATION_REUSE_RESULTS aktif,
+// menyalin hasil task selesai terbaru dengan key yang sama. Mengembalikan true
+// kalau task sudah selesai dengan hasil salinan.
+func (uc *EvaluationUsecase) reuseResult(task *model.EvaluationTask) (bool, error) {
+	if task.ResultKey == "" {
+		key, err := uc.resultKey(task)
+		if err != nil {
+			return false, err
+		}
+		task.ResultKey = key
+	}
+	if task.ResultKey == "" || !config.LoadEvaluationConfig().ReuseResults {
+		return false, nil
+	}
+
+	previous, err := uc.evaluationRepo.FindCompletedByResultKey(task.ResultKey, task.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	task.CV = previous.CV
+	task.Report = previous.Report
+	task.Language = previous.Language
+	task.Extraction = previous.Extraction
+	task.ExtractionQuality = previous.ExtractionQuality
+	task.LowQuality = previous.LowQuality
+	task.Profile = previous.Profile
+	task.Timeline = previous.Timeline
+	task.SkillGap = previous.SkillGap
+	task.CandidateID = previous.CandidateID
+	task.ContextJobs = previous.ContextJobs
+	task.RubricID = previous.RubricID
+	task.CvMatchRate = previous.CvMatchRate
+	task.CvFeedback = previous.CvFeedback
+	task.ProjectScore = previous.ProjectScore
+	task.ProjectFeedback = previous.ProjectFeedback
+	task.OverallSummary = previous.OverallSummary
+	task.Breakdown = previous.Breakdown
+	task.ScoreDetails = previous.ScoreDetails
+	task.Provider = previous.Provider
+	task.Model = previous.Model
+	task.ReusedFromID = &previous.ID
+	task.SetStatus(model.TaskStatusCompleted)
+	log.Printf("Task %s reuses the evaluation of task %s", task.ID, previous.ID)
+	return true, uc.evaluationRepo.UpdateTask(task)
+}
+
+// resultKey adalah hash dari semua input yang menentukan hasil evaluasi: isi
+// file CV & report, versi job konteks, rubric, setting ekstraksi, model LLM dan
+// versi pipeline. Task tanpa job_id memakai versi semua job, karena job hasil RAG
+// berubah kalau ada job yang dibuat, diubah atau dihapus. Kosong kalau task
+// tidak punya dokumen.
+func (uc *EvaluationUsecase) resultKey(task *model.EvaluationTask) (string, error) {
+	if task.CVDocumentID == nil || task.ReportDocumentID == nil {
+		return "", nil
+	}
+	docs, err := uc.documents.GetMany(task.CVDocumentID, task.ReportDocumentID)
+	if err != nil {
+		return "", err
+	}
+	cv, okCV := docs[*task.CVDocumentID]
+	report, okReport := docs[*task.ReportDocumentID]
+	if !okCV || !okReport {
+		return "", nil
+	}
+
+	jobs, err := uc.jobRepo.Fingerprint(task.JobID)
+	if err != nil {
+		return "", err
+	}
+	var jobID, rubricID string
+	if task.JobID != nil {
+		jobID = task.JobID.String()
+	}
+	if task.RubricID != nil {
+		rubricID = task.RubricID.String()
+	}
+	parts := []string{
+		fmt.Sprintf("v%d", pipelineVersion),
+		cv.Hash,
+		report.Hash,
+		jobID,
+		jobs,
+		rubricID,
+		extractor.CacheKey(extractor.Options{Languages: task.OCRLanguages}),
+		uc.taxonomy.Checksum(),
+		uc.llm.ModelID(),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// extractDocuments mengekstrak teks CV & project report dari file yang di-upload
+func (uc *EvaluationUsecase) extractDocuments(ctx context.Context, task *model.EvaluationTask) error {
+	if err := uc.evaluationRepo.UpdateTask(task); err != nil {
+		return err
+	}
+
+	docs, err := uc.documents.GetMany(task.CVDocumentID, task.ReportDocumentID)
+	if err != nil {
+		return err
+	}
+	files := []struct {
+		field      string
+		documentID *uuid.UUID
+		text       *string
+	}{
+		{model.RubricSectionCV, task.CVDocumentID, &task.CV},
+		{model.RubricSectionProjectReport, task.ReportDocumentID, &task.Report},
+	}
+	extraction := map[string]*extractor.Document{}
+	for _, f := range files {
+		if f.documentID == nil {
+			return &ExtractionError{Field: f.field, Err: ErrDocumentNotFound}
+		}
+		doc, ok := docs[*f.documentID]
+		if !ok {
+			return &ExtractionError{Field: f.field, Err: ErrDocumentNotFound}
+		}
+		extracted, err := uc.extractDocument(ctx, &doc, extractor.Options{Languages: task.OCRLanguages})
+		if err != nil {
+			// Timeout, shutdown & gangguan storage bisa berhasil di attempt berikutnya
+			if ctx.Err() != nil || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, errStorageUnavailable) {
+				return fmt.Errorf("extract %s: %w", f.field, err)
+			}
+			return &ExtractionError{Field: f.field, Err: err}
+		}
+		*f.text = extracted.Text
+		extraction[f.field] = extracted
+	}
+
+	// Bahasa kandidat diambil dari CV, atau dari project report kalau CV tidak terdeteksi
+	task.Language = extraction[model.RubricSectionCV].Language
+	if task.Language == "" {
+		task.Language = extraction[model.RubricSectionProjectReport].Language
+	}
+
+	// Kualitas task adalah kualitas dokumen terburuk: satu scan buram cukup
+	// untuk membuat skor tidak bisa dipercaya
+	cfg := config.LoadOCRConfig()
+	task.ExtractionQuality = min(extraction[model.RubricSectionCV].Quality, extraction[model.RubricSectionProjectReport].Quality)
+	task.LowQuality = task.ExtractionQuality < cfg.MinQuality
+
+	// Metode ekstraksi per halaman disimpan supaya bisa dilihat di hasil evaluasi
+	if extractionJSON, err := json.Marshal(extraction); err == nil {
+		task.Extraction = string(extractionJSON)
+	}
+	if err := uc.evaluationRepo.UpdateTask(task); err != nil {
+		return err
+	}
+
+	// Skor kualitas tetap disimpan sebelum task ditolak supaya alasannya terlihat
+	if task.LowQuality && cfg.RejectLowQuality {
+		field := model.RubricSectionCV
+		if extraction[model.RubricSectionProjectReport].Quality < extraction[field].Quality {
+			field = model.RubricSectionProjectReport
+		}
+		return &ExtractionError{Field: field, Err: fmt.Errorf("extraction quality %.2f is below the minimum %.2f, upload a clearer scan", extraction[field].Quality, cfg.MinQuality)}
+	}
+	return nil
+}
+
+// extractDocument mengekstrak satu dokumen (atau memakai cache-nya), dibatasi OCR_TIMEOUT
+func (uc *EvaluationUsecase) extractDocument(ctx context.Context, doc *model.Document, opts extractor.Options) (*extractor.Document, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.LoadOCRConfig().Timeout)
+	defer cancel()
+	return uc.documents.Extract(ctx, doc, opts)
+}
+
+// runStage mengembalikan output stage yang sudah selesai di attempt sebelumnya,
+// atau menjalankan stage tersebut lalu menyimpan input & output-nya
+func runStage[T any](ctx context.Context, p *evaluationPipeline, name, prompt string, schema map[string]any, parse func(string) (*T, error)) (*T, error) {
+	previous := p.stages[name]
+	if previous != nil && previous.Status == model.StageStatusCompleted {
+		var out T
+		if err := json.Unmarshal([]byte(previous.Output), &out); err == nil {
+			return &out, nil
+		}
+		log.Printf("Stored %s stage output for task %s is unreadable, running it again", name, p.task.ID)
+	}
+
+	stage := &model.EvaluationStage{
+		TaskID:    p.task.ID,
+		Stage:     name,
+		Input:     prompt,
+		Output:    "{}",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if previous != nil {
+		stage.Attempts = previous.Attempts
+	}
+
+	var out *T
+	result, attempts, err := p.uc.generateStructured(ctx, prompt, schema, func(text string) error {
+		var err error
+		out, err = parse(text)
+		return err
+	})
+	stage.Attempts += attempts
+	if err != nil {
+		// Saat shutdown stage tidak dicatat gagal; task dilepas kembali ke antrian
+		if ctx.Err() == nil {
+			stage.Status = model.StageStatusFailed
+			stage.Error = err.Error()
+			if saveErr := p.uc.stageRepo.Save(stage); saveErr != nil {
+				log.Printf("Save %s stage for task %s failed: %v", name, p.task.ID, saveErr)
+			}
+		}
+		return nil, fmt.Errorf("%s stage: %w", name, err)
+	}
+
+	output, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	stage.Status = model.StageStatusCompleted
+	stage.Output = string(output)
+	stage.Provider = result.Provider
+	stage.Model = result.Model
+	if err := p.uc.stageRepo.Save(stage); err != nil {
+		return nil, fmt.Errorf("save %s stage: %w", name, err)
+	}
+	p.stages[name] = stage
+	return out, nil
+}
+
+// providers mengembalikan provider & model yang dipakai semua stage (unik, sesuai urutan stage)
+func (p *evaluationPipeline) providers() (string, string) {
+	var providers, models []string
+	for _, name := range pipelineStages {
+		stage, ok := p.stages[name]
+		if !ok {
+			continue
+		}
+		if !contains(providers, stage.Provider) {
+			providers = append(providers, stage.Provider)
+		}
+		if !contains(models, stage.Model) {
+			models = append(models, stage.Model)
+		}
+	}
+	return strings.Join(providers, ","), strings.Join(models, ",")
+}
+
+func contains(values []string, v string) bool {
+	for _, existing := range values {
+		if existing == v {
+			return true
+		}
+	}
+	return false
+}
+
+// generateStructured meminta output dengan response schema lalu memvalidasinya
+// lewat parse. Kalau validasi gagal, LLM diminta memperbaiki jawabannya sampai
+// EVALUATION_MAX_REPAIR_ATTEMPTS kali sebelum menyerah.
+func (uc *EvaluationUsecase) generateStructured(ctx context.Context, prompt string, schema map[string]any, parse func(string) error) (*service.LLMResponse, int, error) {
+	req := service.LLMRequest{
+		Prompt:      prompt,
+		Temperature: 0.1,
+		Schema:      schema,
+	}
+	maxRepairs := config.LoadEvaluationConfig().MaxRepairAttempts
+
+	for attempt := 0; ; attempt++ {
+		result, err := uc.llm.GenerateText(ctx, req)
+		if err != nil {
+			return nil, attempt + 1, err
+		}
+
+		err = parse(result.Text)
+		if err == nil {
+			return result, attempt + 1, nil
+		}
+		if attempt >= maxRepairs {
+			return nil, attempt + 1, &InvalidEvaluationError{Attempts: attempt + 1, Err: err}
+		}
+
+		log.Printf("LLM output invalid (attempt %d/%d), asking for repair: %v", attempt+1, maxRepairs+1, err)
+		req.Prompt = fmt.Sprintf(`%s
+
+Your previous answer was rejected because: %s
+
+Previous answer:
+%s
+
+Return the corrected answer as a single JSON object that satisfies the schema. Do not wrap it in code fences.`, prompt, err, result.Text)
+	}
+}
+
+func extractPrompt(task *model.EvaluationTask) string {
+	return fmt.Sprintf(`
+You are an experienced technical recruiter. Parse the following CV into a structured candidate profile. Only use information written in the CV; use empty strings or empty arrays for anything that is not mentioned. Write dates as they appear in the CV, preferably as "YYYY-MM" or "YYYY", and use "Present" for ongoing roles.
+
+Return your answer STRICTLY in JSON format with this schema:
+{
+  "name": "<full name>",
+  "contact": {"email": "", "phone": ""},
+  "location": "<city, country>",
+  "summary": "<2-3 sentence professional summary>",
+  "education": [{"institution": "", "degree": "", "field": "", "start_date": "", "end_date": ""}],
+  "experiences": [{"title": "", "company": "", "location": "", "start_date": "", "end_date": "", "description": ""}],
+  "skills": ["<skill>"],
+  "certifications": [{"name": "", "issuer": "", "date": ""}],
+  "languages": ["<spoken language>"],
+  "links": ["<portfolio, GitHub, LinkedIn or other URL>"],
+  "projects": [{"name": "", "description": "", "technologies": ["<technology>"]}],
+  "achievements": ["<achievement, with impact or scale when mentioned>"]
+}
+
+CV:
+%s
+`, task.CV)
+}
+
+func cvEvaluationPrompt(task *model.EvaluationTask, jobs []model.Job, profile *model.CandidateProfile, history *model.Timeline, gap *model.SkillGapReport, rubric *model.Rubric) string {
+	jobContext := ""
+	for i, j := range jobs {
+		jobContext += fmt.Sprintf("Job %d: %s\nRequirements: %s\n\n", i+1, j.Title, j.Content)
+	}
+
+	instruction := "Evaluate the following CV against these job requirements:"
+	if task.JobID != nil {
+		instruction = "Evaluate the following CV strictly against this job description only:"
+	}
+
+	profileJSON, _ := json.MarshalIndent(profile, "", "  ")
+
+	return fmt.Sprintf(`
+You are an experienced technical recruiter. %s
+
+%s
+Candidate profile parsed from the CV:
+%s
+%s
+Score each rubric parameter from 1 to 5 based on its criteria. Do not compute overall scores; they are calculated from the weights.
+%s
+Return your answer STRICTLY in JSON format with this schema:
+{
+  "feedback": "<feedback about the CV: strengths, gaps and fit with the job>",
+%s
+}
+
+CV:
+%s
+`, instruction, jobContext, profileJSON, timelinePrompt(history)+skillGapPrompt(gap), languageInstruction(task.Language)+qualityInstruction(task), rubricSectionPrompt(rubric.CVParameters), task.CV)
+}
+
+func projectEvaluationPrompt(task *model.EvaluationTask, jobs []model.Job, rubric *model.Rubric) string {
+	return fmt.Sprintf(`
+You are an experienced technical reviewer. Evaluate the following Project Report against the case study brief.
+
+Case study brief:
+%s
+
+Score each rubric parameter from 1 to 5 based on its criteria. Do not compute overall scores; they are calculated from the weights.
+%s
+Return your answer STRICTLY in JSON format with this schema:
+{
+  "feedback": "<feedback about the Project Report>",
+%s
+}
+
+Report:
+%s
+`, caseStudyBrief(task, jobs), languageInstruction(task.Language)+qualityInstruction(task), rubricSectionPrompt(rubric.ProjectParameters), task.Report)
+}
+
+func synthesisPrompt(task *model.EvaluationTask, jobs []model.Job, profile *model.CandidateProfile, cv, project *dto.SectionEvaluation, scores dto.ScoreDetails) string {
+	titles := make([]string, 0, len(jobs))
+	for _, j := range jobs {
+		titles = append(titles, j.Title)
+	}
+
+	return fmt.Sprintf(`
+You are an experienced technical recruiter. Write the final analysis of this candidate from the evaluation results below. Do not re-score the candidate; the scores are final.
+
+Evaluated for: %s
+
+Candidate summary:
+%s
+
+CV match rate (0-1): %v
+CV feedback:
+%s
+
+Project score (0-10): %v
+Project feedback:
+%s
+%s
+Return your answer STRICTLY in JSON format with this schema:
+{
+  "overall_summary": "<3-5 sentences: overall impression, key strengths, gaps and a recommendation>"
+}
+`, strings.Join(titles, ", "), profile.Summary, scores.CV.Score, cv.Feedback, scores.ProjectReport.Score, project.Feedback, languageInstruction(task.Language))
+}
+
+// languageNames adalah nama bahasa hasil deteksi untuk instruksi di prompt
+var languageNames = map[string]string{
+	extractor.LanguageEnglish:    "English",
+	extractor.LanguageIndonesian: "Indonesian",
+}
+
+// languageInstruction meminta feedback ditulis dalam bahasa kandidat; kosong
+// kalau bahasa tidak terdeteksi
+func languageInstruction(lang string) string {
+	name, ok := languageNames[lang]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("\nWrite all feedback in %s, the language of the candidate's documents. Keep JSON keys in English.\n", name)
+}
+
+// qualityInstruction memperingatkan LLM bahwa teks hasil OCR berkualitas rendah
+// supaya salah eja / karakter rusak tidak dinilai sebagai kesalahan kandidat
+func qualityInstruction(task *model.EvaluationTask) string {
+	if !task.LowQuality {
+		return ""
+	}
+	return "\nThe document text was extracted by OCR from a low-quality scan and may contain recognition errors. Do not penalize the candidate for garbled characters or misspellings that look like OCR errors.\n"
+}
+
+// timelinePrompt menuliskan lama pengalaman & gap hasil perhitungan timeline
+// sebagai fakta, supaya LLM tidak menghitung ulang dari teks CV
+func timelinePrompt(history *model.Timeline) string {
+	if history == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nEmployment timeline computed from the work history dates (authoritative, overlapping roles counted once; use these numbers instead of estimating experience yourself):\n")
+	fmt.Fprintf(&b, "- Total experience: %.1f years (%d months)\n", history.TotalYears, history.TotalMonths)
+	fmt.Fprintf(&b, "- Experience relevant to the job: %.1f years (%d months)\n", history.RelevantYears, history.RelevantMonths)
+	for _, g := range history.Gaps {
+		fmt.Fprintf(&b, "- Employment gap: %s to %s (%d months)\n", g.Start.Format("Jan 2006"), g.End.Format("Jan 2006"), g.Months)
+	}
+	if len(history.Unparsed) > 0 {
+		fmt.Fprintf(&b, "- Roles without readable dates (not counted): %s\n", strings.Join(history.Unparsed, "; "))
+	}
+	return b.String()
+}
+
+// skillGapPrompt menuliskan hasil perbandingan skill per job sebagai fakta
+func skillGapPrompt(gap *model.SkillGapReport) string {
+	if gap == nil || len(gap.Jobs) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\nSkill comparison computed with the skills taxonomy (aliases are already normalized, e.g. Postgres = PostgreSQL):\n")
+	for _, j := range gap.Jobs {
+		fmt.Fprintf(&b, "- %s: required skills matched: %s; missing: %s; nice-to-have matched: %s\n",
+			j.JobTitle, listOrNone(j.Matched), listOrNone(j.Missing), listOrNone(j.MatchedNiceToHave))
+	}
+	return b.String()
+}
+
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	return strings.Join(values, ", ")
+}
+
+// skillGap membandingkan skill kandidat dengan skill tiap job konteks
+func (uc *EvaluationUsecase) skillGap(jobs []model.Job, profile *model.CandidateProfile, cv string) *model.SkillGapReport {
+	report := &model.SkillGapReport{
+		CandidateSkills: uc.taxonomy.CandidateSkills(profile.Skills, cv),
+		Jobs:            make([]model.JobSkillGap, 0, len(jobs)),
+	}
+	for _, j := range jobs {
+		required, niceToHave := uc.jobSkills(j)
+		gap := skills.Gap(report.CandidateSkills, required, niceToHave)
+		gap.JobID = j.ID
+		gap.JobTitle = j.Title
+		report.Jobs = append(report.Jobs, gap)
+	}
+	return report
+}
+
+// jobSkills mengembalikan skill wajib & nice-to-have job. Job yang dibuat
+// sebelum ada taxonomy belum punya skill tersimpan, jadi diekstrak di sini.
+func (uc *EvaluationUsecase) jobSkills(j model.Job) (required, niceToHave []string) {
+	required, niceToHave = j.RequiredSkills, j.NiceToHaveSkills
+	if required == nil && niceToHave == nil {
+		required, niceToHave = uc.taxonomy.JobSkills(j.Content)
+	}
+	return required, niceToHave
+}
+
+// relevantExperience menganggap pengalaman relevan kalau judul/deskripsinya
+// menyebut skill wajib job konteks (dicocokkan lewat taxonomy, jadi "Golang"
+// sama dengan Go) atau kata spesifik dari judul job. Kata umum seperti
+// "Engineer" atau "Senior" diabaikan supaya tidak semua pengalaman relevan.
+func (uc *EvaluationUsecase) relevantExperience(jobs []model.Job) func(model.WorkExperience) bool {
+	required := map[string]bool{}
+	var keywords []string
+	seen := map[string]bool{}
+	for _, j := range jobs {
+		jobRequired, _ := uc.jobSkills(j)
+		for _, s := range jobRequired {
+			required[strings.ToLower(s)] = true
+		}
+		for _, s := range uc.taxonomy.Find(j.Title) {
+			required[strings.ToLower(s)] = true
+		}
+		for _, word := range strings.FieldsFunc(strings.ToLower(j.Title), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+		}) {
+			// Kata yang berupa skill sudah dicocokkan lewat taxonomy di atas
+			if _, known := uc.taxonomy.Normalize(word); known || len(word) < 2 || genericTitleWords[word] || seen[word] {
+				continue
+			}
+			seen[word] = true
+			keywords = append(keywords, word)
+		}
+	}
+
+	return func(exp model.WorkExperience) bool {
+		text := exp.Title + " " + exp.Description
+		for _, s := range uc.taxonomy.Find(text) {
+			if required[strings.ToLower(s)] {
+				return true
+			}
+		}
+		lower := strings.ToLower(text)
+		for _, keyword := range keywords {
+			if skills.ContainsWord(lower, keyword) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// genericTitleWords adalah kata di judul job yang terlalu umum untuk menentukan
+// relevansi: level, jenis kontrak dan nama peran yang dipakai hampir semua job
+var genericTitleWords = map[string]bool{
+	"senior": true, "junior": true, "mid": true, "middle": true, "lead": true, "staff": true, "principal": true,
+	"head": true, "intern": true, "internship": true, "trainee": true, "associate": true, "assistant": true,
+	"manager": true, "specialist": true, "officer": true, "staf": true, "and": true, "dan": true, "of": true,
+	"the": true, "for": true, "with": true, "remote": true, "contract": true, "freelance": true, "part": true, "full": true, "time": true,
+	"i": true, "ii": true, "iii": true, "sr": true, "jr": true,
+	"engineer": true, "engineering": true, "developer": true, "development": true, "programmer": true,
+	"analyst": true, "consultant": true, "administrator": true, "admin": true, "executive": true,
+	"expert": true, "professional": true, "pengembang": true, "analis": true, "konsultan": true,
+}
+
+// caseStudyBrief memakai brief milik job yang dipilih, atau DefaultCaseStudyBrief
+func caseStudyBrief(task *model.EvaluationTask, jobs []model.Job) string {
+	if task.JobID != nil {
+		for _, j := range jobs {
+			if j.ID == *task.JobID && strings.TrimSpace(j.CaseStudyBrief) != "" {
+				return j.CaseStudyBrief
+			}
+		}
+	}
+	return DefaultCaseStudyBrief
+}
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/fadilmartias/cv-analyzer/internal/skills"
+)
+
+func TestRelevantExperience(t *testing.T) {
+	taxonomy, err := skills.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := &EvaluationUsecase{taxonomy: taxonomy}
+	jobs := []model.Job{{
+		Title:          "Senior Backend Engineer",
+		Content:        "Build APIs with Go and PostgreSQL.",
+		RequiredSkills: []string{"Go", "PostgreSQL"},
+	}}
+	tests := []struct {
+		name string
+		exp  model.WorkExperience
+		want bool
+	}{
+		{name: "required skill alias", exp: model.WorkExperience{Title: "Software Developer", Description: "Payment services in Golang"}, want: true},
+		{name: "required skill name", exp: model.WorkExperience{Title: "Programmer", Description: "Maintained postgres schemas"}, want: true},
+		{name: "specific title word", exp: model.WorkExperience{Title: "Backend Developer"}, want: true},
+		{name: "generic role noun only", exp: model.WorkExperience{Title: "Civil Engineer", Description: "Designed bridges"}, want: false},
+		{name: "generic seniority only", exp: model.WorkExperience{Title: "Senior Analyst", Description: "Financial reporting"}, want: false},
+		{name: "skill not required by the job", exp: model.WorkExperience{Title: "Frontend Developer", Description: "React and TypeScript"}, want: false},
+		{name: "go as a common word", exp: model.WorkExperience{Title: "Sales Consultant", Description: "Helped clients go live faster"}, want: false},
+	}
+	relevant := uc.relevantExperience(jobs)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := relevant(tt.exp); got != tt.want {
+				t.Errorf("relevant(%+v) = %t, want %t", tt.exp, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelevantExperienceJobWithoutStoredSkills(t *testing.T) {
+	taxonomy, err := skills.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := &EvaluationUsecase{taxonomy: taxonomy}
+	// Skill diekstrak dari deskripsi & judul job kalau belum tersimpan
+	relevant := uc.relevantExperience([]model.Job{{Title: "Golang Developer", Content: "Requirements:\n- Kafka"}})
+	for _, exp := range []model.WorkExperience{
+		{Title: "Engineer", Description: "Go microservices"},
+		{Title: "Engineer", Description: "Event streaming on Apache Kafka"},
+	} {
+		if !relevant(exp) {
+			t.Errorf("relevant(%+v) = false, want true", exp)
+		}
+	}
+	if relevant(model.WorkExperience{Title: "Developer", Description: "WordPress sites"}) {
+		t.Error("a developer role without job skills counted as relevant")
+	}
+}
@@ -2,42 +2,143 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+	"github.com/fadilmartias/cv-analyzer/internal/extractor"
 	"github.com/fadilmartias/cv-analyzer/internal/model"
 	"github.com/fadilmartias/cv-analyzer/internal/repository"
+	"github.com/fadilmartias/cv-analyzer/internal/response"
 	"github.com/fadilmartias/cv-analyzer/internal/service"
+	"github.com/fadilmartias/cv-analyzer/internal/skills"
+	"github.com/google/uuid"
 	"github.com/pgvector/pgvector-go"
-	"github.com/tidwall/gjson"
+	"gorm.io/gorm"
 )
 
+// InvalidEvaluationError menandakan output LLM tetap tidak valid setelah repair.
+// Error ini permanen: worker tidak me-retry task-nya.
+type InvalidEvaluationError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *InvalidEvaluationError) Error() string {
+	return fmt.Sprintf("evaluation output still invalid after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *InvalidEvaluationError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InvalidEvaluationError) Permanent() bool {
+	return true
+}
+
+// ErrBacklogFull dikembalikan saat antrian evaluasi sudah mencapai WORKER_MAX_BACKLOG
+var ErrBacklogFull = errors.New("evaluation backlog is full")
+
 type EvaluationUsecase struct {
 	evaluationRepo *repository.EvaluationRepository
 	jobRepo        *repository.JobRepository
-	openRouter     service.OpenRouterServiceInterface
-	gemini         service.GeminiServiceInterface
+	queueRepo      *repository.QueueRepository
+	rubricRepo     *repository.RubricRepository
+	stageRepo      *repository.EvaluationStageRepository
+	documents      *DocumentUsecase
+	candidates     *CandidateUsecase
+	taxonomy       *skills.Taxonomy
+	llm            service.LLMProvider
+	embedder       service.Embedder
 }
 
-func NewEvaluationUsecase(evaluationRepo *repository.EvaluationRepository, jobRepo *repository.JobRepository, openRouter service.OpenRouterServiceInterface, gemini service.GeminiServiceInterface) *EvaluationUsecase {
-	return &EvaluationUsecase{evaluationRepo: evaluationRepo, jobRepo: jobRepo, openRouter: openRouter, gemini: gemini}
+func NewEvaluationUsecase(evaluationRepo *repository.EvaluationRepository, jobRepo *repository.JobRepository, queueRepo *repository.QueueRepository, rubricRepo *repository.RubricRepository, stageRepo *repository.EvaluationStageRepository, documents *DocumentUsecase, candidates *CandidateUsecase, taxonomy *skills.Taxonomy, llm service.LLMProvider, embedder service.Embedder) *EvaluationUsecase {
+	return &EvaluationUsecase{evaluationRepo: evaluationRepo, jobRepo: jobRepo, queueRepo: queueRepo, rubricRepo: rubricRepo, stageRepo: stageRepo, documents: documents, candidates: candidates, taxonomy: taxonomy, llm: llm, embedder: embedder}
+}
+
+// CheckBacklog menolak task baru kalau antrian sudah penuh (backpressure)
+func (uc *EvaluationUsecase) CheckBacklog() error {
+	maxBacklog := config.LoadWorkerConfig().MaxBacklog
+	if maxBacklog <= 0 {
+		return nil
+	}
+	pending, err := uc.queueRepo.CountPending(model.QueueKindEvaluate)
+	if err != nil {
+		return err
+	}
+	if pending >= int64(maxBacklog) {
+		return ErrBacklogFull
+	}
+	return nil
 }
 
 func (uc *EvaluationUsecase) Submit(req model.EvaluationTask) (string, error) {
-	req.Status = "processing"
+	if err := uc.CheckBacklog(); err != nil {
+		return "", err
+	}
+
+	// Rubric dipatok saat submit supaya perubahan rubric setelahnya tidak
+	// mempengaruhi task yang sudah masuk antrian
+	if rubric, err := uc.resolveRubric(&req); err == nil && rubric.ID != uuid.Nil {
+		req.RubricID = &rubric.ID
+	}
+
+	req.StatusHistory = nil
+	req.SetStatus(model.TaskStatusUploaded)
+	req.SetStatus(model.TaskStatusExtracting)
 	req.Breakdown = "{}"
+	req.ContextJobs = "[]"
+	req.ScoreDetails = "{}"
+	if req.Extraction == "" {
+		req.Extraction = "{}"
+	}
 	req.CreatedAt = time.Now()
 	req.UpdatedAt = time.Now()
 	if err := uc.evaluationRepo.CreateTask(&req); err != nil {
 		return "", err
 	}
 
-	go uc.EvaluateTask(&req)
+	// Task diproses worker dari antrian, bukan goroutine lepas, supaya tetap
+	// jalan setelah restart. Kalau enqueue gagal, task tetap "extracting" dan
+	// akan diambil oleh recovery saat startup.
+	job := model.QueueJob{
+		TaskID:      req.ID,
+		Kind:        model.QueueKindEvaluate,
+		MaxAttempts: config.LoadQueueConfig().MaxAttempts,
+	}
+	if err := uc.queueRepo.Enqueue(&job); err != nil {
+		log.Printf("Enqueue task %s failed: %v", req.ID, err)
+	}
 
 	return req.ID.String(), nil
 }
 
+// ProcessTask dipanggil worker antrian untuk menjalankan evaluasi satu task
+func (uc *EvaluationUsecase) ProcessTask(ctx context.Context, taskID string) error {
+	task, err := uc.evaluationRepo.FindTaskByID(taskID)
+	if err != nil {
+		return fmt.Errorf("find task: %w", err)
+	}
+	if task.Status == model.TaskStatusCompleted {
+		return nil
+	}
+	return uc.EvaluateTask(ctx, task)
+}
+
+// FailTask menandai task gagal setelah semua attempt habis
+func (uc *EvaluationUsecase) FailTask(taskID string, cause error) error {
+	task, err := uc.evaluationRepo.FindTaskByID(taskID)
+	if err != nil {
+		return err
+	}
+	task.SetStatus(model.TaskStatusFailed)
+	task.Error = cause.Error()
+	return uc.evaluationRepo.UpdateTask(task)
+}
+
 func (uc *EvaluationUsecase) CreateJobEmbedding() error {
 	ctx := context.Background()
 	jobs := []model.Job{
@@ -103,7 +204,7 @@ This is a remote job. You're free to work where you work best: home office, co-w
 		},
 	}
 	for i, job := range jobs {
-		result, err := uc.gemini.GenerateEmbedding(ctx, job.Content)
+		result, err := uc.embedder.GenerateEmbedding(ctx, job.Content)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -115,107 +216,138 @@ This is a remote job. You're free to work where you work best: home office, co-w
 	return nil
 }
 
-func (uc *EvaluationUsecase) EvaluateTask(task *model.EvaluationTask) error {
-	ctx := context.Background()
+// FindJob memastikan job yang dipilih recruiter ada sebelum task dibuat
+func (uc *EvaluationUsecase) FindJob(id string) (*model.Job, error) {
+	job, err := uc.jobRepo.FindJobByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	return job, err
+}
 
-	// 1️⃣ Generate embedding dari CV
-	cvEmb, err := uc.gemini.GenerateEmbedding(ctx, task.CV)
-	log.Println("CV Embedding:", cvEmb)
+// contextJobs menentukan job yang dipakai sebagai konteks evaluasi: job yang
+// dipilih secara eksplisit, atau top-5 hasil RAG kalau task tidak punya job_id
+func (uc *EvaluationUsecase) contextJobs(ctx context.Context, task *model.EvaluationTask) ([]model.Job, error) {
+	if task.JobID != nil {
+		job, err := uc.FindJob(task.JobID.String())
+		if err != nil {
+			return nil, fmt.Errorf("find job %s: %w", task.JobID, err)
+		}
+		// Embedding CV tidak dibutuhkan untuk konteks, tapi disimpan untuk
+		// pre-filter similarity di GET /jobs/:id/candidates
+		if task.CVDocumentID != nil {
+			if _, err := uc.cvEmbedding(ctx, task); err != nil {
+				log.Printf("Embedding CV of task %s failed: %v", task.ID, err)
+			}
+		}
+		return []model.Job{*job}, nil
+	}
+
+	// 1️⃣ Generate embedding dari CV (atau pakai cache untuk file CV yang sama)
+	cvEmb, err := uc.cvEmbedding(ctx, task)
 	if err != nil {
-		task.Status = "failed"
-		_ = uc.evaluationRepo.UpdateTask(task)
-		return err
+		return nil, err
 	}
 
 	cvVector := pgvector.NewVector(cvEmb)
 
 	// 2️⃣ Ambil job descriptions relevan (RAG)
-	jobs, err := uc.jobRepo.SearchJobs(cvVector, 5)
-	log.Println("Jobs:", jobs)
+	return uc.jobRepo.SearchJobs(cvVector, 5)
+}
+
+// cvEmbedding mengambil embedding CV lewat cache dokumen; task tanpa dokumen
+// langsung di-embed
+func (uc *EvaluationUsecase) cvEmbedding(ctx context.Context, task *model.EvaluationTask) ([]float32, error) {
+	if task.CVDocumentID == nil {
+		return uc.embedder.GenerateEmbedding(ctx, task.CV)
+	}
+	doc, err := uc.documents.Get(*task.CVDocumentID)
 	if err != nil {
-		task.Status = "failed"
-		_ = uc.evaluationRepo.UpdateTask(task)
-		return err
+		return nil, fmt.Errorf("find cv document: %w", err)
 	}
+	return uc.documents.Embedding(ctx, doc, extractor.Options{Languages: task.OCRLanguages}, task.CV)
+}
 
-	// 3️⃣ Buat prompt dengan RAG context
-	jobContext := ""
-	for i, j := range jobs {
-		jobContext += fmt.Sprintf("Job %d: %s\nRequirements: %s\n\n", i+1, j.Title, j.Content)
-	}
-
-	log.Println("Job Context:", jobContext)
-
-	prompt := fmt.Sprintf(`
-You are an experienced technical recruiter. Analyze the following CV and Project Report against these job requirements:
-
-%s
-
-Return your answer STRICTLY in JSON format with this schema:
-{
-	"cv_match_rate": <float with 2 decimal places, range 0-1 based on cv breakdown score that converted to percents and then x20>,
-	"cv_feedback": "<feedback about CV>",
-	"project_score": <float with 2 decimal places, range 0-10 based on project breakdown score>,
-	"project_feedback": "<feedback about Project Report>",
-	"overall_summary": "<summary of overall impression, strengths, and areas to improve>",
-  "breakdown": {
-    "cv": {
-	"technical_skills_match": <number 1-5, weight: 40 percents, criteria: backend, databases, APIs, cloud, and AI/LLM exposure>,
-	"experience_level": <number 1-5, weight: 25 percents, criteria: years, project complexity>,
-	"relevant_achievements": <number 1-5, weight: 20 percents, criteria: impact, scale>,
-	"cultural_fit": <number 1-5, weight: 15 percents, criteria: communication, learning attitude>,
-	},
-    "project_report": {
-      "correctness": <number 1-5, weight: 30 percents, criteria: prompt design, chaining, RAG, handling errors>,
-      "code_quality": <number 1-5, weight: 25 percents, criteria: clean, modular, testable>,
-      "resilience": <number 1-5, weight: 20 percents, criteria: handles failures, retries>,
-      "documentation": <number 1-5, weight: 15 percents, criteria: clear README, explanation of trade-offs>,
-      "creativity_or_bonus": <number 1-5, weight: 10 percents, criteria: optional improvements like authentication, deployment, dashboards, etc.>
-    }
-  }
-}
-
-CV:
-%s
-
-Report:
-%s
-`, jobContext, task.CV, task.Report)
-
-	// 4️⃣ Generate evaluation via Gemini
-	result, err := uc.gemini.GenerateContent(ctx, "gemini-2.5-flash", prompt)
-	log.Println("Result:", result.Text())
+// storedContextJobs memuat ulang job dari task.ContextJobs sesuai urutan aslinya,
+// supaya retry memakai job konteks yang sama tanpa embedding ulang
+func (uc *EvaluationUsecase) storedContextJobs(task *model.EvaluationTask) ([]model.Job, error) {
+	var stored []model.ContextJob
+	if err := json.Unmarshal([]byte(task.ContextJobs), &stored); err != nil || len(stored) == 0 {
+		return nil, nil
+	}
+	ids := make([]uuid.UUID, 0, len(stored))
+	for _, j := range stored {
+		ids = append(ids, j.ID)
+	}
+	found, err := uc.jobRepo.FindJobsByIDs(ids)
 	if err != nil {
-		task.Status = "failed"
-		_ = uc.evaluationRepo.UpdateTask(task)
-		return err
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]model.Job, len(found))
+	for _, j := range found {
+		byID[j.ID] = j
+	}
+	jobs := make([]model.Job, 0, len(found))
+	for _, id := range ids {
+		if j, ok := byID[id]; ok {
+			jobs = append(jobs, j)
+		}
 	}
+	return jobs, nil
+}
 
-	log.Println("Result:", result.Text())
-
-	text := result.Text()
-	cvMatchRate := gjson.Get(text, "cv_match_rate").Float()
-	cvFeedback := gjson.Get(text, "cv_feedback").String()
-	projectScore := gjson.Get(text, "project_score").Float()
-	projectFeedback := gjson.Get(text, "project_feedback").String()
-	overallSummary := gjson.Get(text, "overall_summary").String()
-	breakdown := gjson.Get(text, "breakdown").String()
-
-	// 5️⃣ Update task
-	task.CvMatchRate = cvMatchRate
-	task.CvFeedback = cvFeedback
-	task.ProjectScore = projectScore
-	task.ProjectFeedback = projectFeedback
-	task.OverallSummary = overallSummary
-	task.Breakdown = breakdown
-	task.Status = "completed"
-	return uc.evaluationRepo.UpdateTask(task)
+// resolveRubric memilih rubric untuk task: rubric yang sudah dipatok, rubric aktif
+// milik job, rubric default di database, lalu DefaultRubric bawaan
+func (uc *EvaluationUsecase) resolveRubric(task *model.EvaluationTask) (*model.Rubric, error) {
+	if task.RubricID != nil {
+		return uc.rubricRepo.FindByID(task.RubricID.String())
+	}
+	if task.JobID != nil {
+		rubric, err := uc.rubricRepo.FindActive(task.JobID)
+		if err == nil {
+			return rubric, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	rubric, err := uc.rubricRepo.FindActive(nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		fallback := DefaultRubric()
+		return &fallback, nil
+	}
+	return rubric, err
 }
 
 func (uc *EvaluationUsecase) GetResult(id string) (*model.EvaluationTask, error) {
 	return uc.evaluationRepo.FindTaskByID(id)
 }
 
+// ResultFilter adalah filter ListResults
+type ResultFilter = repository.TaskFilter
+
+// ListResults mengambil daftar task, bisa difilter berdasarkan status, job dan profil kandidat
+func (uc *EvaluationUsecase) ListResults(filter ResultFilter, page, pageSize int) ([]model.EvaluationTask, *response.Pagination, error) {
+	offset := (page - 1) * pageSize
+	tasks, total, err := uc.evaluationRepo.ListTasks(filter, offset, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tasks, response.NewPagination(page, pageSize, len(tasks), total), nil
+}
+
+// GetStages mengembalikan progres pipeline evaluasi per stage
+func (uc *EvaluationUsecase) GetStages(task *model.EvaluationTask) ([]model.EvaluationStage, error) {
+	return uc.stageRepo.FindByTask(task.ID)
+}
+
 func (uc *EvaluationUsecase) Test() (string, error) {
-	return uc.gemini.Test()
+	result, err := uc.llm.GenerateText(context.Background(), service.LLMRequest{
+		Prompt:      "Explain how AI works in a few words",
+		Temperature: 0.1,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.Text, nil
 }
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/fadilmartias/cv-analyzer/internal/repository"
+	"github.com/fadilmartias/cv-analyzer/internal/response"
+	"github.com/fadilmartias/cv-analyzer/internal/service"
+	"github.com/fadilmartias/cv-analyzer/internal/skills"
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+)
+
+var ErrJobNotFound = errors.New("job not found")
+
+// defaultSimilarityTopK adalah jumlah CV paling mirip yang disisakan pre-filter
+// similarity kalau top_k tidak diisi
+const defaultSimilarityTopK = 50
+
+type JobUsecase struct {
+	jobRepo        *repository.JobRepository
+	evaluationRepo *repository.EvaluationRepository
+	embedder       service.Embedder
+	taxonomy       *skills.Taxonomy
+}
+
+func NewJobUsecase(jobRepo *repository.JobRepository, evaluationRepo *repository.EvaluationRepository, embedder service.Embedder, taxonomy *skills.Taxonomy) *JobUsecase {
+	return &JobUsecase{jobRepo: jobRepo, evaluationRepo: evaluationRepo, embedder: embedder, taxonomy: taxonomy}
+}
+
+func (uc *JobUsecase) Create(ctx context.Context, title, content, caseStudyBrief string) (*model.Job, error) {
+	job := model.Job{
+		Title:          strings.TrimSpace(title),
+		Content:        strings.TrimSpace(content),
+		CaseStudyBrief: strings.TrimSpace(caseStudyBrief),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	job.RequiredSkills, job.NiceToHaveSkills = uc.taxonomy.JobSkills(job.Content)
+	if err := uc.embed(ctx, &job); err != nil {
+		return nil, err
+	}
+	if err := uc.jobRepo.CreateJob(&job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (uc *JobUsecase) Get(id string) (*model.Job, error) {
+	job, err := uc.jobRepo.FindJobByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	return job, err
+}
+
+func (uc *JobUsecase) List(page, pageSize int) ([]model.Job, *response.Pagination, error) {
+	offset := (page - 1) * pageSize
+	jobs, total, err := uc.jobRepo.ListJobs(offset, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return jobs, response.NewPagination(page, pageSize, len(jobs), total), nil
+}
+
+// Update menyimpan perubahan job. Embedding hanya dihitung ulang kalau
+// deskripsi berubah, supaya tidak memanggil provider embedding tanpa perlu.
+func (uc *JobUsecase) Update(ctx context.Context, id, title, content, caseStudyBrief string) (*model.Job, error) {
+	job, err := uc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	title = strings.TrimSpace(title)
+	content = strings.TrimSpace(content)
+	contentChanged := job.Content != content
+
+	job.Title = title
+	job.Content = content
+	job.CaseStudyBrief = strings.TrimSpace(caseStudyBrief)
+	job.UpdatedAt = time.Now()
+	job.RequiredSkills, job.NiceToHaveSkills = uc.taxonomy.JobSkills(job.Content)
+	if contentChanged {
+		if err := uc.embed(ctx, job); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.jobRepo.UpdateJob(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (uc *JobUsecase) Delete(id string) error {
+	affected, err := uc.jobRepo.DeleteJob(id)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrJobNotFound
+	}
+	return nil
+}
+
+// RankFilter adalah filter RankCandidates
+type RankFilter = repository.RankFilter
+
+// RankedCandidate adalah evaluasi terbaru seorang kandidat untuk job, dengan
+// skor ranking (0-10) dan kemiripan embedding CV dengan job
+type RankedCandidate struct {
+	Task       model.EvaluationTask
+	Score      float64
+	Similarity *float64
+}
+
+// RankCandidates me-ranking kandidat yang sudah dievaluasi untuk job dari skor
+// tertinggi. Skill di filter dinormalisasi dengan taxonomy; status default
+// "completed" karena task yang belum selesai belum punya skor.
+func (uc *JobUsecase) RankCandidates(id string, filter RankFilter, page, pageSize int) ([]RankedCandidate, *response.Pagination, error) {
+	job, err := uc.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if filter.Status == "" {
+		filter.Status = model.TaskStatusCompleted
+	}
+	filter.Skills = uc.taxonomy.NormalizeAll(filter.Skills)
+	filter.EmbeddingModel = uc.embedder.EmbeddingModelID()
+	if filter.VectorPrefilter && filter.TopK <= 0 {
+		filter.TopK = defaultSimilarityTopK
+	}
+
+	offset := (page - 1) * pageSize
+	ranked, total, err := uc.evaluationRepo.RankForJob(job.ID, filter, offset, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(ranked))
+	for _, r := range ranked {
+		ids = append(ids, r.TaskID)
+	}
+	tasks, err := uc.evaluationRepo.FindTasksByIDs(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := make([]RankedCandidate, 0, len(ranked))
+	for _, r := range ranked {
+		task, ok := tasks[r.TaskID]
+		if !ok {
+			continue // dihapus di antara dua query
+		}
+		result = append(result, RankedCandidate{Task: task, Score: r.Score, Similarity: r.Similarity})
+	}
+	return result, response.NewPagination(page, pageSize, len(result), total), nil
+}
+
+// RefreshSkills mengekstrak ulang skill semua job, dipanggil saat startup
+// supaya perubahan taxonomy (SKILLS_TAXONOMY_FILE) langsung berlaku
+func (uc *JobUsecase) RefreshSkills() error {
+	jobs, err := uc.jobRepo.GetJobs()
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		required, niceToHave := uc.taxonomy.JobSkills(jobs[i].Content)
+		if slices.Equal(required, jobs[i].RequiredSkills) && slices.Equal(niceToHave, jobs[i].NiceToHaveSkills) {
+			continue
+		}
+		if err := uc.jobRepo.UpdateJobSkills(jobs[i].ID, required, niceToHave); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *JobUsecase) embed(ctx context.Context, job *model.Job) error {
+	result, err := uc.embedder.GenerateEmbedding(ctx, job.Content)
+	if err != nil {
+		return fmt.Errorf("generate job embedding: %w", err)
+	}
+	job.Embedding = pgvector.NewVector(result)
+	return nil
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/fadilmartias/cv-analyzer/internal/repository"
+	"github.com/fadilmartias/cv-analyzer/internal/response"
+	"github.com/fadilmartias/cv-analyzer/internal/util"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrRubricNotFound = errors.New("rubric not found")
+
+var rubricKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type RubricUsecase struct {
+	rubricRepo *repository.RubricRepository
+	jobRepo    *repository.JobRepository
+}
+
+func NewRubricUsecase(rubricRepo *repository.RubricRepository, jobRepo *repository.JobRepository) *RubricUsecase {
+	return &RubricUsecase{rubricRepo: rubricRepo, jobRepo: jobRepo}
+}
+
+// DefaultRubric adalah rubric bawaan yang dipakai kalau belum ada rubric di database
+func DefaultRubric() model.Rubric {
+	return model.Rubric{
+		Name: "Default Product Engineer (Backend)",
+		CVParameters: []model.RubricParameter{
+			{Key: "technical_skills_match", Name: "Technical Skills Match", Weight: 40, Description: "backend, databases, APIs, cloud, and AI/LLM exposure"},
+			{Key: "experience_level", Name: "Experience Level", Weight: 25, Description: "years, project complexity"},
+			{Key: "relevant_achievements", Name: "Relevant Achievements", Weight: 20, Description: "impact, scale"},
+			{Key: "cultural_fit", Name: "Cultural Fit", Weight: 15, Description: "communication, learning attitude"},
+		},
+		ProjectParameters: []model.RubricParameter{
+			{Key: "correctness", Name: "Correctness", Weight: 30, Description: "prompt design, chaining, RAG, handling errors"},
+			{Key: "code_quality", Name: "Code Quality", Weight: 25, Description: "clean, modular, testable"},
+			{Key: "resilience", Name: "Resilience", Weight: 20, Description: "handles failures, retries"},
+			{Key: "documentation", Name: "Documentation", Weight: 15, Description: "clear README, explanation of trade-offs"},
+			{Key: "creativity_or_bonus", Name: "Creativity / Bonus", Weight: 10, Description: "optional improvements like authentication, deployment, dashboards, etc."},
+		},
+	}
+}
+
+// EnsureDefault menyimpan DefaultRubric kalau tabel rubrics masih kosong
+func (uc *RubricUsecase) EnsureDefault() error {
+	count, err := uc.rubricRepo.Count()
+	if err != nil || count > 0 {
+		return err
+	}
+	rubric := DefaultRubric()
+	rubric.GroupID = uuid.New()
+	rubric.Version = 1
+	rubric.CreatedAt = time.Now()
+	rubric.UpdatedAt = time.Now()
+	return uc.rubricRepo.CreateActive(&rubric)
+}
+
+func (uc *RubricUsecase) Create(rubric model.Rubric) (*model.Rubric, error) {
+	if err := uc.validate(&rubric); err != nil {
+		return nil, err
+	}
+	rubric.ID = uuid.Nil
+	rubric.GroupID = uuid.New()
+	rubric.Version = 1
+	rubric.CreatedAt = time.Now()
+	rubric.UpdatedAt = time.Now()
+	if err := uc.rubricRepo.CreateActive(&rubric); err != nil {
+		return nil, err
+	}
+	return &rubric, nil
+}
+
+// Update tidak mengubah baris yang ada: perubahan disimpan sebagai versi baru
+// yang aktif, versi sebelumnya dinonaktifkan
+func (uc *RubricUsecase) Update(id string, rubric model.Rubric) (*model.Rubric, error) {
+	current, err := uc.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.validate(&rubric); err != nil {
+		return nil, err
+	}
+
+	version, err := uc.rubricRepo.NextVersion(current.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	rubric.ID = uuid.Nil
+	rubric.GroupID = current.GroupID
+	rubric.Version = version
+	rubric.CreatedAt = time.Now()
+	rubric.UpdatedAt = time.Now()
+	if err := uc.rubricRepo.CreateActive(&rubric); err != nil {
+		return nil, err
+	}
+	return &rubric, nil
+}
+
+func (uc *RubricUsecase) Get(id string) (*model.Rubric, error) {
+	rubric, err := uc.rubricRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRubricNotFound
+	}
+	return rubric, err
+}
+
+func (uc *RubricUsecase) List(jobID *uuid.UUID, activeOnly bool, page, pageSize int) ([]model.Rubric, *response.Pagination, error) {
+	offset := (page - 1) * pageSize
+	rubrics, total, err := uc.rubricRepo.List(jobID, activeOnly, offset, pageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rubrics, response.NewPagination(page, pageSize, len(rubrics), total), nil
+}
+
+// Deactivate menonaktifkan rubric; versi lama tetap tersimpan untuk audit
+func (uc *RubricUsecase) Deactivate(id string) error {
+	affected, err := uc.rubricRepo.Deactivate(id)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRubricNotFound
+	}
+	return nil
+}
+
+func (uc *RubricUsecase) validate(rubric *model.Rubric) error {
+	errs := map[string]string{}
+
+	rubric.Name = strings.TrimSpace(rubric.Name)
+	if rubric.Name == "" {
+		errs["name"] = "name is required"
+	}
+	if rubric.JobID != nil {
+		if _, err := uc.jobRepo.FindJobByID(rubric.JobID.String()); err != nil {
+			errs["job_id"] = "job not found"
+		}
+	}
+	validateRubricSection(model.RubricSectionCV, rubric.CVParameters, errs)
+	validateRubricSection(model.RubricSectionProjectReport, rubric.ProjectParameters, errs)
+
+	if len(errs) > 0 {
+		return util.NewFormError("invalid rubric", errs)
+	}
+	return nil
+}
+
+func validateRubricSection(section string, params []model.RubricParameter, errs map[string]string) {
+	if len(params) == 0 {
+		errs[section] = "at least one parameter is required"
+		return
+	}
+
+	seen := map[string]bool{}
+	var total float64
+	for i := range params {
+		p := &params[i]
+		p.Key = strings.TrimSpace(p.Key)
+		p.Name = strings.TrimSpace(p.Name)
+		p.Description = strings.TrimSpace(p.Description)
+
+		field := fmt.Sprintf("%s.%d", section, i)
+		switch {
+		case !rubricKeyPattern.MatchString(p.Key):
+			errs[field+".key"] = "key must be snake_case"
+		case seen[p.Key]:
+			errs[field+".key"] = fmt.Sprintf("duplicate key %q", p.Key)
+		}
+		seen[p.Key] = true
+		if p.Name == "" {
+			p.Name = p.Key
+		}
+		if p.Weight <= 0 {
+			errs[field+".weight"] = "weight must be greater than 0"
+		}
+		total += p.Weight
+	}
+
+	if math.Abs(total-100) > 0.01 {
+		errs[section+".weight"] = fmt.Sprintf("weights must sum to 100, got %v", total)
+	}
+}
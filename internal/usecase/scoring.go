@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/fadilmartias/cv-analyzer/internal/dto"
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+)
+
+// computeScores menghitung skor akhir secara deterministik dari skor rubric 1-5
+// dan bobot rubric: cv_match_rate = rata-rata berbobot x 20 / 100 (0-1),
+// project_score = rata-rata berbobot x 2 (0-10)
+func computeScores(breakdown dto.EvaluationBreakdown, rubric *model.Rubric) dto.ScoreDetails {
+	cv := weightedSection(breakdown.CV, rubric.CVParameters)
+	cv.Score = round2(cv.WeightedAverage * 20 / 100)
+
+	project := weightedSection(breakdown.ProjectReport, rubric.ProjectParameters)
+	project.Score = round2(project.WeightedAverage * 2)
+
+	return dto.ScoreDetails{CV: cv, ProjectReport: project}
+}
+
+func weightedSection(scores map[string]float64, params []model.RubricParameter) dto.ScoreSection {
+	section := dto.ScoreSection{Components: make([]dto.ScoreComponent, 0, len(params))}
+	var total, totalWeight float64
+	for _, p := range params {
+		score := scores[p.Key]
+		weighted := score * p.Weight / 100
+		section.Components = append(section.Components, dto.ScoreComponent{
+			Parameter: p.Key,
+			Score:     score,
+			Weight:    p.Weight,
+			Weighted:  round2(weighted),
+		})
+		total += weighted
+		totalWeight += p.Weight
+	}
+	if totalWeight > 0 {
+		section.WeightedAverage = round2(total * 100 / totalWeight)
+	}
+	return section
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// rubricSectionPrompt menyusun bagian "scores" pada prompt dari parameter rubric satu section
+func rubricSectionPrompt(params []model.RubricParameter) string {
+	var b strings.Builder
+	b.WriteString("  \"scores\": {\n")
+	for i, p := range params {
+		sep := ","
+		if i == len(params)-1 {
+			sep = ""
+		}
+		fmt.Fprintf(&b, "    %q: <number 1-5, %s, weight: %v percents, criteria: %s>%s\n", p.Key, p.Name, p.Weight, p.Description, sep)
+	}
+	b.WriteString("  }")
+	return b.String()
+}
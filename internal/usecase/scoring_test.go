@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/fadilmartias/cv-analyzer/internal/dto"
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+)
+
+func TestComputeScoresDefaultRubric(t *testing.T) {
+	rubric := DefaultRubric()
+	tests := []struct {
+		name        string
+		cv          map[string]float64
+		project     map[string]float64
+		wantCVAvg   float64
+		wantCV      float64
+		wantProjAvg float64
+		wantProject float64
+	}{
+		{
+			name:        "all max",
+			cv:          map[string]float64{"technical_skills_match": 5, "experience_level": 5, "relevant_achievements": 5, "cultural_fit": 5},
+			project:     map[string]float64{"correctness": 5, "code_quality": 5, "resilience": 5, "documentation": 5, "creativity_or_bonus": 5},
+			wantCVAvg:   5,
+			wantCV:      1,
+			wantProjAvg: 5,
+			wantProject: 10,
+		},
+		{
+			name:        "all min",
+			cv:          map[string]float64{"technical_skills_match": 1, "experience_level": 1, "relevant_achievements": 1, "cultural_fit": 1},
+			project:     map[string]float64{"correctness": 1, "code_quality": 1, "resilience": 1, "documentation": 1, "creativity_or_bonus": 1},
+			wantCVAvg:   1,
+			wantCV:      0.2,
+			wantProjAvg: 1,
+			wantProject: 2,
+		},
+		{
+			// cv: 4*.40 + 3*.25 + 5*.20 + 2*.15 = 3.65; project: 5*.30 + 4*.25 + 3*.20 + 2*.15 + 1*.10 = 3.5
+			name:        "mixed",
+			cv:          map[string]float64{"technical_skills_match": 4, "experience_level": 3, "relevant_achievements": 5, "cultural_fit": 2},
+			project:     map[string]float64{"correctness": 5, "code_quality": 4, "resilience": 3, "documentation": 2, "creativity_or_bonus": 1},
+			wantCVAvg:   3.65,
+			wantCV:      0.73,
+			wantProjAvg: 3.5,
+			wantProject: 7,
+		},
+		{
+			name:        "missing parameters count as zero",
+			cv:          map[string]float64{"technical_skills_match": 5},
+			project:     map[string]float64{},
+			wantCVAvg:   2,
+			wantCV:      0.4,
+			wantProjAvg: 0,
+			wantProject: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := computeScores(dto.EvaluationBreakdown{CV: tt.cv, ProjectReport: tt.project}, &rubric)
+			if got.CV.WeightedAverage != tt.wantCVAvg || got.CV.Score != tt.wantCV {
+				t.Errorf("cv = avg %v score %v, want avg %v score %v", got.CV.WeightedAverage, got.CV.Score, tt.wantCVAvg, tt.wantCV)
+			}
+			if got.ProjectReport.WeightedAverage != tt.wantProjAvg || got.ProjectReport.Score != tt.wantProject {
+				t.Errorf("project = avg %v score %v, want avg %v score %v", got.ProjectReport.WeightedAverage, got.ProjectReport.Score, tt.wantProjAvg, tt.wantProject)
+			}
+			if len(got.CV.Components) != len(rubric.CVParameters) || len(got.ProjectReport.Components) != len(rubric.ProjectParameters) {
+				t.Errorf("components = %d cv / %d project, want one per rubric parameter", len(got.CV.Components), len(got.ProjectReport.Components))
+			}
+		})
+	}
+}
+
+func TestWeightedSection(t *testing.T) {
+	tests := []struct {
+		name       string
+		scores     map[string]float64
+		params     []model.RubricParameter
+		wantAvg    float64
+		wantWeight []float64
+	}{
+		{
+			name:       "components keep raw score and weighted value",
+			scores:     map[string]float64{"a": 4, "b": 4, "c": 5},
+			params:     []model.RubricParameter{{Key: "a", Weight: 33}, {Key: "b", Weight: 33}, {Key: "c", Weight: 34}},
+			wantAvg:    4.34,
+			wantWeight: []float64{1.32, 1.32, 1.7},
+		},
+		{
+			// Bobot yang tidak berjumlah 100 dinormalisasi ke total bobot
+			name:       "weights not summing to 100",
+			scores:     map[string]float64{"a": 5, "b": 1},
+			params:     []model.RubricParameter{{Key: "a", Weight: 30}, {Key: "b", Weight: 10}},
+			wantAvg:    4,
+			wantWeight: []float64{1.5, 0.1},
+		},
+		{
+			name:       "rounded to two decimals",
+			scores:     map[string]float64{"a": 5, "b": 4, "c": 4},
+			params:     []model.RubricParameter{{Key: "a", Weight: 1}, {Key: "b", Weight: 1}, {Key: "c", Weight: 1}},
+			wantAvg:    4.33,
+			wantWeight: []float64{0.05, 0.04, 0.04},
+		},
+		{
+			name:    "no parameters",
+			scores:  map[string]float64{"a": 5},
+			wantAvg: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := weightedSection(tt.scores, tt.params)
+			if got.WeightedAverage != tt.wantAvg {
+				t.Errorf("weighted average = %v, want %v", got.WeightedAverage, tt.wantAvg)
+			}
+			if len(got.Components) != len(tt.params) {
+				t.Fatalf("components = %d, want %d", len(got.Components), len(tt.params))
+			}
+			for i, c := range got.Components {
+				p := tt.params[i]
+				if c.Parameter != p.Key || c.Weight != p.Weight || c.Score != tt.scores[p.Key] || c.Weighted != tt.wantWeight[i] {
+					t.Errorf("component %d = %+v, want %s weight %v score %v weighted %v", i, c, p.Key, p.Weight, tt.scores[p.Key], tt.wantWeight[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fadilmartias/cv-analyzer/internal/config"
+	"github.com/fadilmartias/cv-analyzer/internal/model"
+	"github.com/fadilmartias/cv-analyzer/internal/repository"
+	"github.com/google/uuid"
+)
+
+// TaskProcessor dijalankan worker untuk setiap job yang berhasil di-claim
+type TaskProcessor interface {
+	ProcessTask(ctx context.Context, taskID string) error
+	FailTask(taskID string, cause error) error
+}
+
+// EvaluationWorker adalah pool berisi beberapa goroutine yang menarik job dari antrian.
+// Jumlah goroutine dibatasi WorkerConfig.Concurrency dan jarak antar task dibatasi limiter.
+type EvaluationWorker struct {
+	queueRepo *repository.QueueRepository
+	processor TaskProcessor
+	cfg       *config.QueueConfig
+	workerCfg *config.WorkerConfig
+	limiter   *intervalLimiter
+	id        string
+}
+
+func NewEvaluationWorker(queueRepo *repository.QueueRepository, processor TaskProcessor, cfg *config.QueueConfig, workerCfg *config.WorkerConfig) *EvaluationWorker {
+	hostname, _ := os.Hostname()
+	return &EvaluationWorker{
+		queueRepo: queueRepo,
+		processor: processor,
+		cfg:       cfg,
+		workerCfg: workerCfg,
+		limiter:   newIntervalLimiter(workerCfg.TaskInterval),
+		id:        fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+	}
+}
+
+// Recover dipanggil saat startup: job yang lease-nya kadaluarsa dikembalikan
+// ke antrian dan task yang belum selesai tanpa job dibuatkan job baru.
+func (w *EvaluationWorker) Recover() error {
+	requeued, err := w.queueRepo.RequeueExpired()
+	if err != nil {
+		return fmt.Errorf("requeue expired jobs: %w", err)
+	}
+	orphaned, err := w.queueRepo.EnqueueOrphanedTasks(model.QueueKindEvaluate, w.cfg.MaxAttempts)
+	if err != nil {
+		return fmt.Errorf("enqueue orphaned tasks: %w", err)
+	}
+	if requeued > 0 || orphaned > 0 {
+		log.Printf("Queue recovery: %d expired job(s) requeued, %d orphaned task(s) enqueued", requeued, orphaned)
+	}
+	return nil
+}
+
+// Start menjalankan sejumlah loop polling sesuai concurrency dan
+// menunggu semuanya selesai setelah ctx dibatalkan
+func (w *EvaluationWorker) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 1; i <= w.workerCfg.Concurrency; i++ {
+		wg.Add(1)
+		go func(workerID string) {
+			defer wg.Done()
+			w.run(ctx, workerID)
+		}(fmt.Sprintf("%s-%d", w.id, i))
+	}
+	log.Printf("Evaluation worker pool %s started with %d worker(s)", w.id, w.workerCfg.Concurrency)
+	wg.Wait()
+	log.Printf("Evaluation worker pool %s stopped", w.id)
+}
+
+func (w *EvaluationWorker) run(ctx context.Context, workerID string) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Habiskan antrian dulu sebelum menunggu tick berikutnya
+		for w.runOnce(ctx, workerID) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Requeue berkala supaya job milik worker lain yang mati ikut diambil
+			if _, err := w.queueRepo.RequeueExpired(); err != nil {
+				log.Printf("Requeue expired jobs failed: %v", err)
+			}
+		}
+	}
+}
+
+// runOnce meng-claim dan memproses satu job. Mengembalikan true kalau ada job yang diproses.
+func (w *EvaluationWorker) runOnce(ctx context.Context, workerID string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	job, err := w.queueRepo.Claim(model.QueueKindEvaluate, workerID, w.cfg.LeaseDuration)
+	if err != nil {
+		log.Printf("Claim job failed: %v", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	w.handle(ctx, workerID, job)
+	return true
+}
+
+func (w *EvaluationWorker) handle(ctx context.Context, workerID string, job *model.QueueJob) {
+	taskID := job.TaskID.String()
+
+	if job.Attempts > job.MaxAttempts {
+		w.fail(job, fmt.Errorf("max attempts (%d) exceeded", job.MaxAttempts))
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go w.heartbeat(jobCtx, cancel, workerID, job.ID)
+
+	// Lease sudah dipegang (dan diperpanjang heartbeat) selama menunggu giliran
+	err := w.limiter.Wait(jobCtx)
+	if err == nil {
+		log.Printf("Worker %s processing task %s (attempt %d/%d)", workerID, taskID, job.Attempts, job.MaxAttempts)
+		err = w.process(jobCtx, taskID)
+	}
+	if err == nil {
+		if err := w.queueRepo.Complete(job.ID); err != nil {
+			log.Printf("Complete job %s failed: %v", job.ID, err)
+		}
+		return
+	}
+
+	// Shutdown: kembalikan job ke antrian tanpa menghabiskan attempt
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		log.Printf("Task %s interrupted by shutdown, releasing", taskID)
+		if err := w.queueRepo.Release(job.ID); err != nil {
+			log.Printf("Release job %s failed: %v", job.ID, err)
+		}
+		return
+	}
+
+	// Lease diambil alih worker lain, job bukan milik kita lagi
+	if jobCtx.Err() != nil {
+		log.Printf("Task %s abandoned after losing lease: %v", taskID, err)
+		return
+	}
+
+	if job.Attempts >= job.MaxAttempts || isPermanent(err) {
+		w.fail(job, err)
+		return
+	}
+
+	delay := w.cfg.RetryDelay * time.Duration(job.Attempts)
+	log.Printf("Task %s failed on attempt %d, retrying in %v: %v", taskID, job.Attempts, delay, err)
+	if err := w.queueRepo.Retry(job.ID, time.Now().Add(delay), err.Error()); err != nil {
+		log.Printf("Retry job %s failed: %v", job.ID, err)
+	}
+}
+
+// process membungkus ProcessTask supaya panic tidak mematikan worker
+func (w *EvaluationWorker) process(ctx context.Context, taskID string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while processing task: %v", r)
+		}
+	}()
+	return w.processor.ProcessTask(ctx, taskID)
+}
+
+func (w *EvaluationWorker) fail(job *model.QueueJob, cause error) {
+	log.Printf("Task %s failed permanently: %v", job.TaskID, cause)
+	if err := w.queueRepo.Fail(job.ID, cause.Error()); err != nil {
+		log.Printf("Fail job %s failed: %v", job.ID, err)
+	}
+	if err := w.processor.FailTask(job.TaskID.String(), cause); err != nil {
+		log.Printf("Mark task %s failed: %v", job.TaskID, err)
+	}
+}
+
+// heartbeat memperpanjang lease selama job berjalan; kalau lease hilang
+// (diambil worker lain) proses dibatalkan.
+func (w *EvaluationWorker) heartbeat(ctx context.Context, cancel context.CancelFunc, workerID string, jobID uuid.UUID) {
+	ticker := time.NewTicker(w.cfg.LeaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ok, err := w.queueRepo.Heartbeat(jobID, workerID, w.cfg.LeaseDuration)
+			if err != nil {
+				log.Printf("Heartbeat job %s failed: %v", jobID, err)
+				continue
+			}
+			if !ok {
+				log.Printf("Lease for job %s lost, cancelling", jobID)
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// isPermanent mengecek apakah error menandai dirinya tidak perlu di-retry
+func isPermanent(err error) bool {
+	var p interface{ Permanent() bool }
+	return errors.As(err, &p) && p.Permanent()
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// intervalLimiter memberi jarak minimal antar task yang dimulai di seluruh pool,
+// supaya panggilan LLM tetap terkontrol walau upload datang bersamaan.
+type intervalLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newIntervalLimiter(interval time.Duration) *intervalLimiter {
+	return &intervalLimiter{interval: interval}
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}